@@ -1,16 +1,16 @@
 package main
 
 import (
+	"AIChallengeNewsAPI/internal/app"
 	"AIChallengeNewsAPI/internal/config"
-	"AIChallengeNewsAPI/internal/httpServer"
 	"AIChallengeNewsAPI/internal/logger"
-	"AIChallengeNewsAPI/internal/repository"
-	newsUsecase "AIChallengeNewsAPI/internal/usecase/news"
-	"fmt"
-	"github.com/gorilla/mux"
+	"context"
+	"flag"
 	"log/slog"
-	"net/http"
-	"time"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata"
 
 	_ "github.com/lib/pq"
 )
@@ -23,32 +23,25 @@ func main() {
 
 	log.Info("starting application", slog.Any("config", cfg))
 
-	connectionString := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Database.Host, cfg.Database.Port, cfg.Database.User, cfg.Database.Password, cfg.Database.DBName)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	repository, err := repository.NewRepository(connectionString, log)
-	if err != nil {
-		log.Warn("failed to create repository", slog.String("error", err.Error()))
+	if args := flag.Args(); len(args) > 0 && args[0] == "migrate" {
+		if err := app.Migrate(ctx, cfg, log, args[1:], os.Stdout); err != nil {
+			log.Warn("migrate failed", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
 		return
 	}
 
-	newsUsecase, err := newsUsecase.NewNewsUseCase(log, repository, 10, 10*time.Minute)
+	application, err := app.New(ctx, cfg, log)
 	if err != nil {
-		log.Warn("failed to create newsUsecase", slog.String("error", err.Error()))
-		return
+		log.Warn("failed to create application", slog.String("error", err.Error()))
+		os.Exit(1)
 	}
-	go newsUsecase.Start()
-	defer newsUsecase.Stop()
-
-	httpHandler := httpServer.NewHTTPHandler(newsUsecase)
-	router := mux.NewRouter()
-	httpHandler.RegisterRoutes(router)
 
-	log.Info(fmt.Sprintf("Server is running at https://%s:%d", cfg.HTTPServer.Host, cfg.HTTPServer.Port))
-	err = http.ListenAndServe(fmt.Sprintf("%s:%d", cfg.HTTPServer.Host, cfg.HTTPServer.Port), router)
-	if err != nil {
-		log.Warn("failed to start server", slog.String("error", err.Error()))
-		return
+	if err := application.Run(ctx); err != nil {
+		log.Warn("application stopped with error", slog.String("error", err.Error()))
+		os.Exit(1)
 	}
-	return
 }
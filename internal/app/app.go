@@ -0,0 +1,171 @@
+package app
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"AIChallengeNewsAPI/internal/httpServer"
+	"AIChallengeNewsAPI/internal/interfaces"
+	"AIChallengeNewsAPI/internal/repository"
+	"AIChallengeNewsAPI/internal/repository/memory"
+	"AIChallengeNewsAPI/internal/repository/sqlite"
+	newsUsecase "AIChallengeNewsAPI/internal/usecase/news"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// App связывает репозиторий, парсер новостей и HTTP-сервер и управляет
+// их запуском и остановкой.
+type App struct {
+	cfg     *config.Config
+	log     *slog.Logger
+	repo    interfaces.RepositoryInter
+	useCase *newsUsecase.NewsUseCase
+	server  *http.Server
+}
+
+func New(ctx context.Context, cfg *config.Config, log *slog.Logger) (*App, error) {
+	repo, err := newRepository(ctx, cfg.Database, log)
+	if err != nil {
+		return nil, err
+	}
+
+	useCase, err := newsUsecase.NewNewsUseCase(log, repo, 10, cfg.Scraper, cfg.Sources)
+	if err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("failed to create newsUsecase: %w", err)
+	}
+
+	router := mux.NewRouter()
+	httpServer.NewHTTPHandler(useCase).RegisterRoutes(router)
+
+	return &App{
+		cfg:     cfg,
+		log:     log,
+		repo:    repo,
+		useCase: useCase,
+		server: &http.Server{
+			Addr:    fmt.Sprintf("%s:%d", cfg.HTTPServer.Host, cfg.HTTPServer.Port),
+			Handler: router,
+		},
+	}, nil
+}
+
+// Run запускает парсинг и HTTP-сервер и блокируется до отмены ctx
+// (сигнал остановки) или ошибки сервера, после чего останавливает всё
+// по порядку: см. shutdown.
+func (a *App) Run(ctx context.Context) error {
+	// Парсинг не наследует ctx: по сигналу текущий запуск должен успеть
+	// завершиться, а прерывается он только по истечении ShutdownTimeout.
+	scraperCtx, cancelScraper := context.WithCancel(context.Background())
+	defer cancelScraper()
+
+	scraperDone := make(chan struct{})
+	go func() {
+		defer close(scraperDone)
+		a.useCase.Start(scraperCtx)
+	}()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		a.log.Info(fmt.Sprintf("Server is running at http://%s", a.server.Addr))
+		if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		a.log.Info("shutdown signal received")
+	case err := <-serverErr:
+		a.log.Warn("failed to start server", slog.String("error", err.Error()))
+		runErr = err
+	}
+
+	a.shutdown(scraperDone, cancelScraper)
+	return runErr
+}
+
+// shutdown останавливает приложение:
+//  1. прекращает планирование запусков и закрывает потоки SSE;
+//  2. перестаёт принимать запросы и ждёт активные (HTTP_SHUTDOWN_TIMEOUT);
+//  3. ждёт текущие запуски парсинга (SCRAPE_SHUTDOWN_TIMEOUT), затем прерывает
+//     их — итог запуска при этом всё равно записывается в scrape_runs;
+//  4. закрывает пул соединений с базой.
+func (a *App) shutdown(scraperDone <-chan struct{}, cancelScraper context.CancelFunc) {
+	a.useCase.Stop()
+
+	httpCtx, cancel := context.WithTimeout(context.Background(), a.cfg.HTTPServer.ShutdownTimeout)
+	defer cancel()
+	if err := a.server.Shutdown(httpCtx); err != nil {
+		a.log.Warn("failed to shutdown http server gracefully", slog.String("error", err.Error()))
+		a.server.Close()
+	}
+	a.log.Info("http server stopped")
+
+	timer := time.NewTimer(a.cfg.Scraper.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-scraperDone:
+	case <-timer.C:
+		a.log.Warn("scrape runs did not finish in time, cancelling",
+			slog.Duration("timeout", a.cfg.Scraper.ShutdownTimeout))
+		cancelScraper()
+		<-scraperDone
+	}
+	a.log.Info("scraper stopped")
+
+	if err := a.repo.Close(); err != nil {
+		a.log.Warn("failed to close database", slog.String("error", err.Error()))
+	}
+	a.log.Info("application stopped")
+}
+
+// newRepository создаёт хранилище по STORAGE_DRIVER; для postgres и sqlite
+// при включённом AutoMigrate сразу применяет миграции.
+func newRepository(ctx context.Context, cfg config.DatabaseConfig, log *slog.Logger) (interfaces.RepositoryInter, error) {
+	switch cfg.Driver {
+	case "memory":
+		log.Warn("using in-memory storage: news are lost on restart")
+		return memory.NewRepository(), nil
+
+	case "postgres":
+		repo, err := repository.NewRepository(ctx, connectionString(cfg), log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create repository: %w", err)
+		}
+		if cfg.AutoMigrate {
+			if err := repo.Migrate(ctx, log); err != nil {
+				repo.Close()
+				return nil, fmt.Errorf("failed to apply migrations: %w", err)
+			}
+		}
+		return repo, nil
+
+	case "sqlite":
+		repo, err := sqlite.NewRepository(ctx, cfg.SQLitePath, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create repository: %w", err)
+		}
+		if cfg.AutoMigrate {
+			if err := repo.Migrate(ctx, log); err != nil {
+				repo.Close()
+				return nil, fmt.Errorf("failed to apply migrations: %w", err)
+			}
+		}
+		return repo, nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+func connectionString(cfg config.DatabaseConfig) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName)
+}
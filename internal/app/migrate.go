@@ -0,0 +1,90 @@
+package app
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"AIChallengeNewsAPI/internal/lib/migrate"
+	"AIChallengeNewsAPI/internal/repository"
+	"AIChallengeNewsAPI/internal/repository/sqlite"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// migrator — хранилище со встроенными миграциями схемы.
+type migrator interface {
+	Migrate(ctx context.Context, log *slog.Logger) error
+	MigrateDown(ctx context.Context, log *slog.Logger, steps int) error
+	MigrationStatus(ctx context.Context) ([]migrate.Status, error)
+	Close() error
+}
+
+// Migrate выполняет подкоманду migrate: up применяет все новые миграции,
+// down откатывает последние steps (по умолчанию одну), status печатает
+// состояние миграций в out.
+func Migrate(ctx context.Context, cfg *config.Config, log *slog.Logger, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	repo, err := newMigrator(ctx, cfg.Database, log)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	switch args[0] {
+	case "up":
+		return repo.Migrate(ctx, log)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("steps must be a positive number, got %q", args[1])
+			}
+		}
+		return repo.MigrateDown(ctx, log, steps)
+
+	case "status":
+		statuses, err := repo.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.Applied {
+				applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+}
+
+func newMigrator(ctx context.Context, cfg config.DatabaseConfig, log *slog.Logger) (migrator, error) {
+	var (
+		repo migrator
+		err  error
+	)
+	switch cfg.Driver {
+	case "postgres":
+		repo, err = repository.NewRepository(ctx, connectionString(cfg), log)
+	case "sqlite":
+		repo, err = sqlite.NewRepository(ctx, cfg.SQLitePath, log)
+	default:
+		return nil, fmt.Errorf("storage driver %q has no migrations", cfg.Driver)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+	return repo, nil
+}
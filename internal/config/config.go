@@ -4,6 +4,7 @@ import (
 	"flag"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
+	"time"
 )
 
 const (
@@ -14,20 +15,132 @@ type Config struct {
 	Env        string `env:"ENV" env-default:"local"`
 	Database   DatabaseConfig
 	HTTPServer HTTPServerConfig
+	Scraper    ScraperConfig
+	Sources    []SourceConfig
+}
+
+type ScraperConfig struct {
+	Interval    time.Duration `env:"SCRAPE_INTERVAL" env-default:"10m"`
+	SourcesPath string        `env:"SOURCES_PATH" env-default:"./config/sources.yaml"`
+	HTTPTimeout time.Duration `env:"SCRAPE_HTTP_TIMEOUT" env-default:"15s"`
+	HTTPRetries int           `env:"SCRAPE_HTTP_RETRIES" env-default:"3"`
+	UserAgent   string        `env:"SCRAPE_USER_AGENT" env-default:"Mozilla/5.0 (compatible; NewsAggregatorBot/1.0)"`
+	MaxBodySize int64         `env:"SCRAPE_MAX_BODY_SIZE" env-default:"10485760"`
+	// SourceConcurrency — число одновременных загрузок статей одного источника,
+	// MaxConcurrency — общий предел одновременных загрузок по всем источникам.
+	SourceConcurrency int `env:"SCRAPE_SOURCE_CONCURRENCY" env-default:"4"`
+	MaxConcurrency    int `env:"SCRAPE_MAX_CONCURRENCY" env-default:"8"`
+	// RunStaleAfter — через сколько незавершённый запуск источника считается
+	// брошенным (процесс упал) и больше не блокирует новые запуски.
+	RunStaleAfter time.Duration `env:"SCRAPE_RUN_STALE_AFTER" env-default:"1h"`
+	// SourceStaleAfter — сколько источник может не обновляться успешно, прежде
+	// чем считается устаревшим; если устарели все источники, /readyz отвечает 503.
+	SourceStaleAfter time.Duration `env:"SCRAPE_SOURCE_STALE_AFTER" env-default:"3h"`
+	// Дедлайны этапов парсинга: загрузка и разбор одной страницы списка,
+	// одной статьи, сохранение одной новости и весь запуск источника целиком.
+	ListingTimeout time.Duration `env:"SCRAPE_LISTING_TIMEOUT" env-default:"1m"`
+	ArticleTimeout time.Duration `env:"SCRAPE_ARTICLE_TIMEOUT" env-default:"1m"`
+	StoreTimeout   time.Duration `env:"SCRAPE_STORE_TIMEOUT" env-default:"10s"`
+	RunTimeout     time.Duration `env:"SCRAPE_RUN_TIMEOUT" env-default:"30m"`
+	// ShutdownTimeout — сколько при остановке ждать завершения текущих
+	// запусков, прежде чем прервать их.
+	ShutdownTimeout time.Duration `env:"SCRAPE_SHUTDOWN_TIMEOUT" env-default:"30s"`
+	// Перепроверка опубликованных статей на изменения: статьи моложе
+	// RevisionWindow загружаются заново не чаще раза в RevisionInterval,
+	// не больше RevisionBatch за запуск источника. RevisionWindow = 0
+	// выключает перепроверку.
+	RevisionWindow   time.Duration `env:"SCRAPE_REVISION_WINDOW" env-default:"48h"`
+	RevisionInterval time.Duration `env:"SCRAPE_REVISION_INTERVAL" env-default:"1h"`
+	RevisionBatch    int           `env:"SCRAPE_REVISION_BATCH" env-default:"20"`
+}
+
+// SourceConfig описывает один новостной сайт. Добавление или отключение
+// источника — правка файла SOURCES_PATH, а не кода.
+type SourceConfig struct {
+	Name        string         `yaml:"name"`
+	URLs        []string       `yaml:"urls"`
+	Parser      string         `yaml:"parser"`
+	Interval    time.Duration  `yaml:"interval"`
+	Enabled     *bool          `yaml:"enabled"`
+	Encoding    string         `yaml:"encoding"`
+	Concurrency int            `yaml:"concurrency"`
+	Schedule    ScheduleConfig `yaml:"schedule"`
+	Selector    SelectorConfig `yaml:"selector"`
+	Feed        FeedConfig     `yaml:"feed"`
+	HTTP        HTTPConfig     `yaml:"http"`
+}
+
+// ScheduleConfig задаёт расписание источника вместо фиксированного Interval:
+// либо cron-выражение, либо профиль. Профиль "moex" опрашивает источник
+// каждые TradingInterval в торговые часы Московской биржи (10:00–18:50 МСК,
+// будни, кроме Holidays) и каждые OffHoursInterval в остальное время.
+type ScheduleConfig struct {
+	Cron             string        `yaml:"cron"`
+	Profile          string        `yaml:"profile"`
+	TradingInterval  time.Duration `yaml:"trading_interval"`
+	OffHoursInterval time.Duration `yaml:"off_hours_interval"`
+	Holidays         []string      `yaml:"holidays"`
+}
+
+// HTTPConfig переопределяет для источника настройки загрузки страниц
+// из ScraperConfig; незаданные поля берутся оттуда.
+type HTTPConfig struct {
+	Timeout     time.Duration     `yaml:"timeout"`
+	Retries     *int              `yaml:"retries"`
+	UserAgent   string            `yaml:"user_agent"`
+	Headers     map[string]string `yaml:"headers"`
+	MaxBodySize int64             `yaml:"max_body_size"`
+}
+
+// FeedConfig настраивает парсер типа "feed" (RSS/Atom). Если Body задан,
+// текст статьи берётся со страницы по этому селектору, иначе — из самой ленты.
+type FeedConfig struct {
+	Body string `yaml:"body"`
+}
+
+// SelectorConfig задаёт разметку сайта для парсера типа "selector".
+// Пустой Title/Link означает сам элемент Item, пустой *Attr — текст элемента.
+// Относительные ссылки разрешаются от адреса страницы списка.
+type SelectorConfig struct {
+	Item       string `yaml:"item"`
+	Title      string `yaml:"title"`
+	Link       string `yaml:"link"`
+	LinkAttr   string `yaml:"link_attr"`
+	Date       string `yaml:"date"`
+	DateAttr   string `yaml:"date_attr"`
+	DateLayout string `yaml:"date_layout"`
+	Timezone   string `yaml:"timezone"`
+	Provider   string `yaml:"provider"`
+	Body       string `yaml:"body"`
+}
+
+func (s SourceConfig) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
 }
 
 type HTTPServerConfig struct {
 	Host string `env:"HTTP_SERVER_HOST" env-default:"localhost"`
 	Port int    `env:"HTTP_SERVER_PORT" env-default:"8080"`
+	// ShutdownTimeout — сколько ждать завершения активных запросов при остановке.
+	ShutdownTimeout time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"10s"`
 }
 
+// DatabaseConfig описывает хранилище. Driver: postgres (по умолчанию),
+// sqlite — файл SQLitePath без отдельного сервера базы, или memory — всё
+// в памяти процесса, для локального запуска без базы.
 type DatabaseConfig struct {
-	User           string `env:"POSTGRES_USER" env-required:"true"`
-	Password       string `env:"POSTGRES_PASSWORD" env-required:"true"`
-	DBName         string `env:"POSTGRES_DB" env-required:"true"`
-	Port           string `env:"POSTGRES_PORT" env-default:"5432"`
-	Host           string `env:"POSTGRES_HOST" env-default:"localhost"`
-	RepositoryPath string `env:"REPOSITORY_PATH" env-required:"true"`
+	Driver   string `env:"STORAGE_DRIVER" env-default:"postgres"`
+	User     string `env:"POSTGRES_USER"`
+	Password string `env:"POSTGRES_PASSWORD"`
+	DBName   string `env:"POSTGRES_DB"`
+	Port     string `env:"POSTGRES_PORT" env-default:"5432"`
+	Host     string `env:"POSTGRES_HOST" env-default:"localhost"`
+
+	// SQLitePath — файл базы для драйвера sqlite; каталог создаётся при старте.
+	SQLitePath string `env:"SQLITE_PATH" env-default:"./data/news.db"`
+	// AutoMigrate — применять миграции схемы при старте приложения. Если
+	// выключено, миграции применяются командой migrate up.
+	AutoMigrate bool `env:"DB_AUTO_MIGRATE" env-default:"true"`
 }
 
 func MustLoad() *Config {
@@ -48,6 +161,49 @@ func MustLoad() *Config {
 		panic("failed to read config" + err.Error())
 	}
 
+	switch cfg.Database.Driver {
+	case "postgres":
+		if cfg.Database.User == "" || cfg.Database.Password == "" || cfg.Database.DBName == "" {
+			panic("POSTGRES_USER, POSTGRES_PASSWORD and POSTGRES_DB are required for the postgres storage driver")
+		}
+	case "sqlite":
+		if cfg.Database.SQLitePath == "" {
+			panic("SQLITE_PATH is required for the sqlite storage driver")
+		}
+	case "memory":
+	default:
+		panic("unknown storage driver " + cfg.Database.Driver)
+	}
+
+	if cfg.Scraper.Interval <= 0 {
+		panic("scrape interval must be positive")
+	}
+	if cfg.Scraper.HTTPTimeout <= 0 || cfg.Scraper.HTTPRetries < 0 || cfg.Scraper.MaxBodySize <= 0 {
+		panic("scrape http timeout and max body size must be positive, retries must not be negative")
+	}
+	if cfg.Scraper.SourceConcurrency <= 0 || cfg.Scraper.MaxConcurrency <= 0 {
+		panic("scrape concurrency limits must be positive")
+	}
+	if cfg.Scraper.ListingTimeout <= 0 || cfg.Scraper.ArticleTimeout <= 0 ||
+		cfg.Scraper.StoreTimeout <= 0 || cfg.Scraper.RunTimeout <= 0 {
+		panic("scrape stage timeouts must be positive")
+	}
+	if cfg.Scraper.SourceStaleAfter <= 0 {
+		panic("source stale threshold must be positive")
+	}
+	if cfg.HTTPServer.ShutdownTimeout <= 0 || cfg.Scraper.ShutdownTimeout <= 0 {
+		panic("shutdown timeouts must be positive")
+	}
+	if cfg.Scraper.RevisionWindow < 0 || cfg.Scraper.RevisionInterval <= 0 || cfg.Scraper.RevisionBatch <= 0 {
+		panic("revision window must not be negative, revision interval and batch must be positive")
+	}
+
+	sources, err := loadSources(cfg.Scraper.SourcesPath)
+	if err != nil {
+		panic("failed to read sources config: " + err.Error())
+	}
+	cfg.Sources = sources
+
 	return &cfg
 
 }
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/robfig/cron/v3"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+type sourcesFile struct {
+	Sources []SourceConfig `yaml:"sources"`
+}
+
+func loadSources(path string) ([]SourceConfig, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	var file sourcesFile
+	if err := cleanenv.ReadConfig(path, &file); err != nil {
+		return nil, err
+	}
+
+	if err := validateSources(file.Sources); err != nil {
+		return nil, err
+	}
+	return file.Sources, nil
+}
+
+// validateSources проверяет только структуру описания источников;
+// известен ли тип парсера, решает фабрика парсеров при старте.
+func validateSources(sources []SourceConfig) error {
+	names := make(map[string]struct{}, len(sources))
+
+	for i, source := range sources {
+		if source.Name == "" {
+			return fmt.Errorf("source #%d: name is required", i+1)
+		}
+		if _, ok := names[source.Name]; ok {
+			return fmt.Errorf("source %q: duplicate name", source.Name)
+		}
+		names[source.Name] = struct{}{}
+
+		if source.Parser == "" {
+			return fmt.Errorf("source %q: parser is required", source.Name)
+		}
+		if len(source.URLs) == 0 {
+			return fmt.Errorf("source %q: at least one url is required", source.Name)
+		}
+		for _, rawURL := range source.URLs {
+			u, err := url.Parse(rawURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("source %q: invalid url %q", source.Name, rawURL)
+			}
+		}
+		if source.Interval < 0 {
+			return fmt.Errorf("source %q: interval must not be negative", source.Name)
+		}
+		if source.Parser == "selector" {
+			if err := validateSelector(source.Selector); err != nil {
+				return fmt.Errorf("source %q: %v", source.Name, err)
+			}
+		}
+		if source.HTTP.Timeout < 0 || source.HTTP.MaxBodySize < 0 || (source.HTTP.Retries != nil && *source.HTTP.Retries < 0) {
+			return fmt.Errorf("source %q: http timeout, retries and max_body_size must not be negative", source.Name)
+		}
+		if err := validateSchedule(source.Schedule); err != nil {
+			return fmt.Errorf("source %q: %v", source.Name, err)
+		}
+		if source.Concurrency < 0 {
+			return fmt.Errorf("source %q: concurrency must not be negative", source.Name)
+		}
+		if source.Encoding != "" {
+			if _, err := htmlindex.Get(source.Encoding); err != nil {
+				return fmt.Errorf("source %q: unknown encoding %q", source.Name, source.Encoding)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateSelector(selector SelectorConfig) error {
+	if selector.Item == "" {
+		return fmt.Errorf("selector.item is required")
+	}
+	if selector.Body == "" {
+		return fmt.Errorf("selector.body is required")
+	}
+	if selector.Date != "" && selector.DateLayout == "" {
+		return fmt.Errorf("selector.date_layout is required when selector.date is set")
+	}
+	if selector.Timezone != "" {
+		if _, err := time.LoadLocation(selector.Timezone); err != nil {
+			return fmt.Errorf("selector.timezone: %v", err)
+		}
+	}
+	return nil
+}
+
+func validateSchedule(schedule ScheduleConfig) error {
+	if schedule.Cron != "" && schedule.Profile != "" {
+		return fmt.Errorf("schedule.cron and schedule.profile are mutually exclusive")
+	}
+	if schedule.Cron != "" {
+		sched, err := cron.ParseStandard(schedule.Cron)
+		if err != nil {
+			return fmt.Errorf("schedule.cron: %v", err)
+		}
+		// Выражение вроде "0 0 30 2 *" разбирается, но не срабатывает никогда:
+		// Next возвращает нулевое время.
+		if sched.Next(time.Now()).IsZero() {
+			return fmt.Errorf("schedule.cron: %q never fires", schedule.Cron)
+		}
+	}
+	switch schedule.Profile {
+	case "", "moex":
+	default:
+		return fmt.Errorf("unknown schedule.profile %q", schedule.Profile)
+	}
+	if schedule.TradingInterval < 0 || schedule.OffHoursInterval < 0 {
+		return fmt.Errorf("schedule intervals must not be negative")
+	}
+	for _, holiday := range schedule.Holidays {
+		if _, err := time.Parse(time.DateOnly, holiday); err != nil {
+			return fmt.Errorf("schedule.holidays: %q is not a YYYY-MM-DD date", holiday)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule ScheduleConfig
+		wantErr  string
+	}{
+		{name: "empty"},
+		{name: "cron", schedule: ScheduleConfig{Cron: "*/5 * * * *"}},
+		{name: "leap day", schedule: ScheduleConfig{Cron: "0 0 29 2 *"}},
+		{name: "moex profile", schedule: ScheduleConfig{Profile: "moex", Holidays: []string{"2025-01-01"}}},
+		{name: "invalid cron", schedule: ScheduleConfig{Cron: "every minute"}, wantErr: "schedule.cron"},
+		{name: "cron that never fires", schedule: ScheduleConfig{Cron: "0 0 30 2 *"}, wantErr: "never fires"},
+		{name: "cron and profile", schedule: ScheduleConfig{Cron: "* * * * *", Profile: "moex"}, wantErr: "mutually exclusive"},
+		{name: "unknown profile", schedule: ScheduleConfig{Profile: "nyse"}, wantErr: "unknown schedule.profile"},
+		{name: "negative interval", schedule: ScheduleConfig{Profile: "moex", TradingInterval: -time.Minute}, wantErr: "must not be negative"},
+		{name: "invalid holiday", schedule: ScheduleConfig{Profile: "moex", Holidays: []string{"01.01.2025"}}, wantErr: "schedule.holidays"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchedule(tt.schedule)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateSchedule() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateSchedule() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,27 +1,56 @@
 package entity
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrNewsNotFound = errors.New("news not found")
 
 type News struct {
+	ID          int
 	Title       string
 	Link        string
 	Source      string
+	Category    string
 	Text        string
 	PublishedAt time.Time
+	// ContentHash — ContentHash(Title, Text) на момент последней загрузки
+	// статьи; у новостей, сохранённых до появления хеша, пустой.
+	// Служебное поле, в ответы API не попадает.
+	ContentHash string `json:"-"`
+	// SourceKey — имя источника из конфигурации, которым собрана статья.
+	// Source заполняет парсер (домен, провайдер из ленты), поэтому для
+	// выборок по источнику конфигурации нужен отдельный ключ. Служебное
+	// поле, в ответы API не попадает.
+	SourceKey string `json:"-"`
+}
+
+// ContentHash — sha256 заголовка и текста статьи. Пробелы и переносы строк
+// нормализуются, чтобы правки одной лишь вёрстки не считались изменением.
+func ContentHash(title, text string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(title+"\n"+text), " ")))
+	return hex.EncodeToString(sum[:])
 }
 
 type NewsDigest struct {
 	Title       string
 	Link        string
 	Source      string
+	Category    string
+	Summary     string
 	PublishedAt time.Time
 }
 
-func (n *News) convertToNewsDigest() *NewsDigest {
+func (n *News) ConvertToNewsDigest() *NewsDigest {
 	return &NewsDigest{
 		Title:       n.Title,
 		Link:        n.Link,
 		Source:      n.Source,
+		Category:    n.Category,
 		PublishedAt: n.PublishedAt,
 	}
 }
@@ -33,3 +62,65 @@ type NewsRepo struct {
 	Source      string
 	PublishedAt time.Time
 }
+
+type NewsFilter struct {
+	Limit      int
+	Sources    []string
+	Categories []string
+	From       time.Time
+	To         time.Time
+	After      *NewsCursor
+}
+
+// NewsCursor указывает на последнюю отданную новость; следующая страница
+// начинается строго после неё в порядке (published_at DESC, id DESC).
+type NewsCursor struct {
+	PublishedAt time.Time
+	ID          int
+}
+
+type NewsPage struct {
+	Items []News
+	Next  *NewsCursor
+}
+
+type NewsSearchResult struct {
+	News
+	Rank     float64
+	Headline string
+}
+
+// NewsRecheckFilter выбирает статьи источника для проверки на изменения:
+// опубликованные не раньше PublishedAfter и не проверявшиеся с CheckedBefore.
+// SourceKey сравнивается с News.SourceKey.
+type NewsRecheckFilter struct {
+	SourceKey      string
+	PublishedAfter time.Time
+	CheckedBefore  time.Time
+	Limit          int
+}
+
+// NewsRevision — изменение уже сохранённой статьи, найденное при повторной
+// загрузке. Diff — unified diff от прежней версии (заголовок первой строкой,
+// затем текст) к новой.
+type NewsRevision struct {
+	ID           int
+	NewsID       int
+	PreviousHash string
+	ContentHash  string
+	Diff         string
+	DetectedAt   time.Time
+}
+
+// ScrapeFailure — статья, которую не удалось загрузить, разобрать или
+// сохранить; она будет повторена на следующем запуске парсинга.
+type ScrapeFailure struct {
+	Source      string
+	Link        string
+	Title       string
+	Reason      string
+	Attempts    int
+	LastAttempt time.Time
+	// Abandoned — попытки исчерпаны, статья временно не загружается.
+	Abandoned bool
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrRunInProgress = errors.New("scrape run already in progress")
+
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusPartial   = "partial"
+	RunStatusFailed    = "failed"
+	RunStatusAbandoned = "abandoned"
+)
+
+// ScrapeRun — один запуск парсинга источника со счётчиками по этапам.
+type ScrapeRun struct {
+	ID         int
+	Source     string
+	Status     string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Digests    int
+	Fetched    int
+	Inserted   int
+	Failed     int
+	Error      string
+}
+
+type ScrapeRunFilter struct {
+	Source string
+	Limit  int
+}
+
+// SourceStatus — состояние источника по последним запускам в этом процессе.
+// Источник устаревший (Stale), если успешного запуска не было дольше порога.
+// Состояние хранится только в памяти: после перезапуска поля Last* пусты,
+// пока источник не отработает снова; история запусков — в scrape_runs.
+type SourceStatus struct {
+	Source        string     `json:"source"`
+	LastRunAt     *time.Time `json:"last_run_at"`
+	LastStatus    string     `json:"last_status"`
+	LastSuccessAt *time.Time `json:"last_success_at"`
+	LastError     string     `json:"last_error"`
+	LastErrorAt   *time.Time `json:"last_error_at"`
+	PendingRetry  int        `json:"pending_retry"`
+	QueueDepth    int64      `json:"queue_depth"`
+	Stale         bool       `json:"stale"`
+}
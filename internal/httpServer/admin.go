@@ -0,0 +1,81 @@
+package httpServer
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultRunsLimit = 50
+	maxRunsLimit     = 500
+)
+
+type scrapeRunsResponse struct {
+	Items []entity.ScrapeRun `json:"items"`
+}
+
+type failuresResponse struct {
+	Items []entity.ScrapeFailure `json:"items"`
+}
+
+func (h *HTTPHandler) GetScrapeRunsHandler(w http.ResponseWriter, r *http.Request) {
+
+	filter := entity.ScrapeRunFilter{
+		Source: r.URL.Query().Get("source"),
+		Limit:  defaultRunsLimit,
+	}
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxRunsLimit {
+			writeJSONError(w, http.StatusBadRequest, "parameter 'limit' must be between 1 and 500")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	runs, err := h.UseCase.GetScrapeRuns(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Failed to get scrape runs", http.StatusInternalServerError)
+		return
+	}
+
+	response := scrapeRunsResponse{Items: runs}
+	if response.Items == nil {
+		response.Items = []entity.ScrapeRun{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		http.Error(w, "Failed to encode scrape runs to JSON", http.StatusInternalServerError)
+		return
+	}
+
+}
+
+// GetFailuresHandler отдаёт статьи, ожидающие повтора, с причиной последней
+// неудачи и числом попыток, последние неудачи первыми.
+func (h *HTTPHandler) GetFailuresHandler(w http.ResponseWriter, r *http.Request) {
+
+	source := r.URL.Query().Get("source")
+
+	response := failuresResponse{Items: []entity.ScrapeFailure{}}
+	for _, failure := range h.UseCase.Failures() {
+		if source == "" || strings.EqualFold(failure.Source, source) {
+			response.Items = append(response.Items, failure)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		http.Error(w, "Failed to encode failures to JSON", http.StatusInternalServerError)
+		return
+	}
+
+}
@@ -0,0 +1,43 @@
+package httpServer
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Курсор непрозрачен для клиента: base64url от "<published_at в наносекундах>:<id>".
+
+func encodeCursor(cursor *entity.NewsCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	raw := fmt.Sprintf("%d:%d", cursor.PublishedAt.UnixNano(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(value string) (*entity.NewsCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || id <= 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &entity.NewsCursor{PublishedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
@@ -0,0 +1,125 @@
+package httpServer
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"encoding/json"
+	"html"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorilla/feeds"
+)
+
+const (
+	feedTitle         = "News Aggregator API"
+	feedDescription   = "Финансовые новости, собранные из подключённых источников"
+	feedSummaryLength = 300
+)
+
+func (h *HTTPHandler) RSSFeedHandler(w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.buildFeed(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	if err := feed.WriteRss(w); err != nil {
+		http.Error(w, "Failed to render RSS feed", http.StatusInternalServerError)
+	}
+}
+
+func (h *HTTPHandler) AtomFeedHandler(w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.buildFeed(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	if err := feed.WriteAtom(w); err != nil {
+		http.Error(w, "Failed to render Atom feed", http.StatusInternalServerError)
+	}
+}
+
+func (h *HTTPHandler) JSONFeedHandler(w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.buildFeed(w, r)
+	if !ok {
+		return
+	}
+
+	jsonFeed := (&feeds.JSON{Feed: feed}).JSONFeed()
+	jsonFeed.FeedUrl = feed.Link.Href
+
+	w.Header().Set("Content-Type", "application/feed+json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(jsonFeed); err != nil {
+		http.Error(w, "Failed to render JSON feed", http.StatusInternalServerError)
+	}
+}
+
+// buildFeed собирает общую модель ленты из тех же данных, что отдаёт /news,
+// с теми же параметрами фильтрации. При ошибке ответ уже записан.
+func (h *HTTPHandler) buildFeed(w http.ResponseWriter, r *http.Request) (*feeds.Feed, bool) {
+	filter, err := parseNewsFilter(r.URL.Query())
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	newsList, err := h.UseCase.GetLatestNews(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Failed to get news", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	feed := &feeds.Feed{
+		Title:       feedTitle,
+		Description: feedDescription,
+		Link:        &feeds.Link{Href: selfURL(r), Rel: "self"},
+		Author:      &feeds.Author{Name: feedTitle},
+	}
+
+	for _, news := range newsList {
+		if news.PublishedAt.After(feed.Updated) {
+			feed.Updated = news.PublishedAt
+		}
+		feed.Add(newFeedItem(news))
+	}
+
+	return feed, true
+}
+
+// В качестве GUID используется исходная ссылка на статью: она уникальна
+// в хранилище и не меняется между выгрузками ленты.
+func newFeedItem(news entity.News) *feeds.Item {
+	return &feeds.Item{
+		Id:          news.Link,
+		IsPermaLink: "true",
+		Title:       news.Title,
+		Link:        &feeds.Link{Href: news.Link},
+		Author:      &feeds.Author{Name: news.Source},
+		Description: summarize(news.Text, feedSummaryLength),
+		Content:     html.EscapeString(strings.TrimSpace(news.Text)),
+		Created:     news.PublishedAt,
+		Updated:     news.PublishedAt,
+	}
+}
+
+func summarize(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
+
+func selfURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
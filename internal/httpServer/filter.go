@@ -0,0 +1,89 @@
+package httpServer
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultNewsLimit = 10
+	maxNewsLimit     = 1000
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: message})
+}
+
+func parseNewsFilter(query url.Values) (entity.NewsFilter, error) {
+	filter := entity.NewsFilter{
+		Limit:      defaultNewsLimit,
+		Sources:    multiValue(query, "source"),
+		Categories: multiValue(query, "category"),
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxNewsLimit {
+			return filter, fmt.Errorf("parameter 'limit' must be between 1 and %d", maxNewsLimit)
+		}
+		filter.Limit = limit
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeParam(query, "to"); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return filter, fmt.Errorf("parameter 'from' must not be after 'to'")
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		if filter.After, err = decodeCursor(cursor); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+// multiValue поддерживает как повторяющиеся параметры (?source=a&source=b),
+// так и перечисление через запятую (?source=a,b).
+func multiValue(query url.Values, key string) []string {
+	var values []string
+	for _, raw := range query[key] {
+		for _, value := range strings.Split(raw, ",") {
+			value = strings.TrimSpace(value)
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func parseTimeParam(query url.Values, key string) (time.Time, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parameter '%s' must be an RFC3339 timestamp", key)
+	}
+	return t, nil
+}
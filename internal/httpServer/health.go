@@ -0,0 +1,87 @@
+package httpServer
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type statusResponse struct {
+	Status  string                `json:"status"`
+	Sources []entity.SourceStatus `json:"sources"`
+}
+
+// HealthzHandler отвечает, пока процесс жив и обслуживает запросы (liveness).
+func (h *HTTPHandler) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// ReadyzHandler проверяет, что база доступна и её схема создана, а также что
+// не все источники устарели. Иначе отвечает 503, и оркестратор снимает
+// экземпляр с балансировки.
+func (h *HTTPHandler) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+	defer cancel()
+
+	response := healthResponse{Status: "ready", Checks: map[string]string{}}
+	code := http.StatusOK
+
+	if err := h.UseCase.CheckStorage(ctx); err != nil {
+		response.Checks["database"] = err.Error()
+		code = http.StatusServiceUnavailable
+	} else {
+		response.Checks["database"] = "ok"
+	}
+
+	if allSourcesStale(h.UseCase.SourceStatuses()) {
+		response.Checks["sources"] = "all sources are stale"
+		code = http.StatusServiceUnavailable
+	} else {
+		response.Checks["sources"] = "ok"
+	}
+
+	if code != http.StatusOK {
+		response.Status = "not ready"
+	}
+	writeHealth(w, code, response)
+}
+
+// StatusHandler показывает состояние каждого источника: последний запуск,
+// последний успешный запуск, последнюю ошибку и признак устаревания.
+func (h *HTTPHandler) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	sources := h.UseCase.SourceStatuses()
+
+	response := statusResponse{Status: "ok", Sources: sources}
+	if allSourcesStale(sources) {
+		response.Status = "stale"
+	}
+	writeHealth(w, http.StatusOK, response)
+}
+
+func allSourcesStale(sources []entity.SourceStatus) bool {
+	if len(sources) == 0 {
+		return false
+	}
+	for _, source := range sources {
+		if !source.Stale {
+			return false
+		}
+	}
+	return true
+}
+
+func writeHealth(w http.ResponseWriter, code int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(response)
+}
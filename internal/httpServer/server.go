@@ -1,19 +1,36 @@
 package httpServer
 
 import (
-	usecase "AIChallengeNewsAPI/internal/usecase/news"
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/interfaces"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+type newsPageResponse struct {
+	Items      []entity.News `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+type searchResponse struct {
+	Items []entity.NewsSearchResult `json:"items"`
+}
+
+type revisionsResponse struct {
+	Items []entity.NewsRevision `json:"items"`
+}
+
 type HTTPHandler struct {
-	UseCase *usecase.NewsUseCase
+	UseCase interfaces.NewsUseCase
 }
 
-func NewHTTPHandler(useCase *usecase.NewsUseCase) *HTTPHandler {
+func NewHTTPHandler(useCase interfaces.NewsUseCase) *HTTPHandler {
 	return &HTTPHandler{
 		UseCase: useCase,
 	}
@@ -21,20 +38,29 @@ func NewHTTPHandler(useCase *usecase.NewsUseCase) *HTTPHandler {
 
 func (h *HTTPHandler) GetLatestNewsHandler(w http.ResponseWriter, r *http.Request) {
 
-	k, err := strconv.Atoi(r.URL.Query().Get("limit"))
-	if err != nil || k <= 0 {
-		k = 10
+	filter, err := parseNewsFilter(r.URL.Query())
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	newsList, err := h.UseCase.GetLatestNews(k)
+	page, err := h.UseCase.GetNewsPage(r.Context(), filter)
 	if err != nil {
 		http.Error(w, "Failed to get news", http.StatusInternalServerError)
 		return
 	}
 
+	response := newsPageResponse{
+		Items:      page.Items,
+		NextCursor: encodeCursor(page.Next),
+	}
+	if response.Items == nil {
+		response.Items = []entity.News{}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 
-	err = json.NewEncoder(w).Encode(newsList)
+	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
 		http.Error(w, "Failed to encode news to JSON", http.StatusInternalServerError)
 		return
@@ -42,6 +68,111 @@ func (h *HTTPHandler) GetLatestNewsHandler(w http.ResponseWriter, r *http.Reques
 
 }
 
+func (h *HTTPHandler) SearchNewsHandler(w http.ResponseWriter, r *http.Request) {
+
+	text := strings.TrimSpace(r.URL.Query().Get("q"))
+	if text == "" {
+		writeJSONError(w, http.StatusBadRequest, "parameter 'q' is required")
+		return
+	}
+
+	// Выдача поиска упорядочена по релевантности, а курсор задаёт позицию
+	// в порядке (published_at, id), поэтому постраничного поиска нет.
+	if r.URL.Query().Has("cursor") {
+		writeJSONError(w, http.StatusBadRequest, "parameter 'cursor' is not supported for search")
+		return
+	}
+
+	filter, err := parseNewsFilter(r.URL.Query())
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.UseCase.SearchNews(r.Context(), text, filter)
+	if err != nil {
+		http.Error(w, "Failed to search news", http.StatusInternalServerError)
+		return
+	}
+
+	response := searchResponse{Items: results}
+	if response.Items == nil {
+		response.Items = []entity.NewsSearchResult{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		http.Error(w, "Failed to encode news to JSON", http.StatusInternalServerError)
+		return
+	}
+
+}
+
+func (h *HTTPHandler) GetNewsByIdHandler(w http.ResponseWriter, r *http.Request) {
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		writeJSONError(w, http.StatusBadRequest, "invalid news id")
+		return
+	}
+
+	news, err := h.UseCase.GetNewsById(r.Context(), id)
+	if errors.Is(err, entity.ErrNewsNotFound) {
+		writeJSONError(w, http.StatusNotFound, "news not found")
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get news", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(news)
+	if err != nil {
+		http.Error(w, "Failed to encode news to JSON", http.StatusInternalServerError)
+		return
+	}
+
+}
+
+// GetNewsRevisionsHandler отдаёт изменения статьи, найденные при повторных
+// загрузках, новые первыми; Diff — unified diff от прежней версии к новой.
+func (h *HTTPHandler) GetNewsRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		writeJSONError(w, http.StatusBadRequest, "invalid news id")
+		return
+	}
+
+	revisions, err := h.UseCase.GetNewsRevisions(r.Context(), id)
+	if errors.Is(err, entity.ErrNewsNotFound) {
+		writeJSONError(w, http.StatusNotFound, "news not found")
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get news revisions", http.StatusInternalServerError)
+		return
+	}
+
+	response := revisionsResponse{Items: revisions}
+	if response.Items == nil {
+		response.Items = []entity.NewsRevision{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		http.Error(w, "Failed to encode news revisions to JSON", http.StatusInternalServerError)
+		return
+	}
+
+}
+
 func (h *HTTPHandler) HomeHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/news", http.StatusSeeOther)
 }
@@ -50,4 +181,22 @@ func (h *HTTPHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/", h.HomeHandler).Methods("GET")
 
 	router.HandleFunc("/news", h.GetLatestNewsHandler).Methods("GET")
+	router.HandleFunc("/news/search", h.SearchNewsHandler).Methods("GET")
+	router.HandleFunc("/news/stream", h.StreamNewsHandler).Methods("GET")
+	router.HandleFunc("/news/{id}", h.GetNewsByIdHandler).Methods("GET")
+	router.HandleFunc("/news/{id}/revisions", h.GetNewsRevisionsHandler).Methods("GET")
+
+	router.HandleFunc("/feeds/rss", h.RSSFeedHandler).Methods("GET")
+	router.HandleFunc("/feeds/atom", h.AtomFeedHandler).Methods("GET")
+	router.HandleFunc("/feeds/json", h.JSONFeedHandler).Methods("GET")
+
+	router.HandleFunc("/admin/runs", h.GetScrapeRunsHandler).Methods("GET")
+	router.HandleFunc("/admin/failures", h.GetFailuresHandler).Methods("GET")
+
+	router.HandleFunc("/healthz", h.HealthzHandler).Methods("GET")
+	router.HandleFunc("/readyz", h.ReadyzHandler).Methods("GET")
+	router.HandleFunc("/status", h.StatusHandler).Methods("GET")
+	router.Handle("/metrics", promhttp.Handler()).Methods("GET")
+
+	router.Use(metricsMiddleware)
 }
@@ -0,0 +1,298 @@
+package httpServer
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/repository/memory"
+	usecase "AIChallengeNewsAPI/internal/usecase/news"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestRouter собирает маршруты поверх настоящего use case без источников.
+func newTestRouter(t *testing.T, repo *memory.Repository) *mux.Router {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	useCase, err := usecase.NewNewsUseCase(log, repo, 10, config.ScraperConfig{MaxConcurrency: 1}, nil)
+	if err != nil {
+		t.Fatalf("NewNewsUseCase: %v", err)
+	}
+	t.Cleanup(useCase.Stop)
+
+	router := mux.NewRouter()
+	NewHTTPHandler(useCase).RegisterRoutes(router)
+	return router
+}
+
+// seedNews сохраняет count новостей, по одной в минуту начиная с base;
+// ID совпадает с номером новости.
+func seedNews(t *testing.T, repo *memory.Repository, count int, base time.Time) {
+	t.Helper()
+
+	var news []entity.News
+	for i := 1; i <= count; i++ {
+		news = append(news, entity.News{
+			Title:       fmt.Sprintf("Новость %d", i),
+			Link:        fmt.Sprintf("https://example.com/news/%d", i),
+			Source:      "example.com",
+			SourceKey:   "example",
+			Text:        fmt.Sprintf("Текст новости %d.", i),
+			ContentHash: fmt.Sprintf("hash-%d", i),
+			PublishedAt: base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	if _, err := repo.UpsertNewsBatch(context.Background(), news); err != nil {
+		t.Fatalf("UpsertNewsBatch: %v", err)
+	}
+}
+
+func get(t *testing.T, handler http.Handler, target string, response any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if response != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), response); err != nil {
+			t.Fatalf("GET %s: decode response: %v", target, err)
+		}
+	}
+	return rec
+}
+
+func TestGetLatestNewsPagination(t *testing.T) {
+	repo := memory.NewRepository()
+	seedNews(t, repo, 5, time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC))
+	handler := newTestRouter(t, repo)
+
+	var ids []int
+	target := "/news?limit=2"
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination does not terminate")
+		}
+		var page newsPageResponse
+		if rec := get(t, handler, target, &page); rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d", target, rec.Code)
+		}
+		for _, news := range page.Items {
+			ids = append(ids, news.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		target = "/news?limit=2&cursor=" + url.QueryEscape(page.NextCursor)
+	}
+
+	if fmt.Sprint(ids) != fmt.Sprint([]int{5, 4, 3, 2, 1}) {
+		t.Errorf("ids across pages = %v, want [5 4 3 2 1]", ids)
+	}
+}
+
+func TestGetLatestNewsEmpty(t *testing.T) {
+	rec := get(t, newTestRouter(t, memory.NewRepository()), "/news", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	if body := rec.Body.String(); body != "{\"items\":[]}\n" {
+		t.Errorf("body = %q, want an empty items array", body)
+	}
+}
+
+func TestGetLatestNewsFilters(t *testing.T) {
+	repo := memory.NewRepository()
+	base := time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC)
+	seedNews(t, repo, 5, base)
+	handler := newTestRouter(t, repo)
+
+	from := url.QueryEscape(base.Add(2 * time.Minute).Format(time.RFC3339))
+	to := url.QueryEscape(base.Add(4 * time.Minute).Format(time.RFC3339))
+
+	var page newsPageResponse
+	if rec := get(t, handler, "/news?from="+from+"&to="+to, &page); rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	var ids []int
+	for _, news := range page.Items {
+		ids = append(ids, news.ID)
+	}
+	if fmt.Sprint(ids) != fmt.Sprint([]int{4, 3, 2}) {
+		t.Errorf("ids = %v, want [4 3 2]", ids)
+	}
+}
+
+func TestGetLatestNewsRejectsInvalidParameters(t *testing.T) {
+	handler := newTestRouter(t, memory.NewRepository())
+
+	for _, target := range []string{
+		"/news?limit=0",
+		"/news?limit=1001",
+		"/news?limit=abc",
+		"/news?from=yesterday",
+		"/news?from=2024-10-26T00:00:00Z&to=2024-10-25T00:00:00Z",
+		"/news?cursor=not-a-cursor",
+	} {
+		rec := get(t, handler, target, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s: status %d, want 400", target, rec.Code)
+			continue
+		}
+		var response errorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil || response.Error == "" {
+			t.Errorf("GET %s: body %q is not a JSON error", target, rec.Body.String())
+		}
+	}
+}
+
+func TestSearchNewsHandler(t *testing.T) {
+	repo := memory.NewRepository()
+	seedNews(t, repo, 3, time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC))
+	handler := newTestRouter(t, repo)
+
+	var response searchResponse
+	if rec := get(t, handler, "/news/search?q="+url.QueryEscape("новости 2"), &response); rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	if len(response.Items) != 1 || response.Items[0].ID != 2 {
+		t.Errorf("items = %+v, want news 2", response.Items)
+	}
+
+	response = searchResponse{}
+	if rec := get(t, handler, "/news/search?q="+url.QueryEscape("золото"), &response); rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	if response.Items == nil || len(response.Items) != 0 {
+		t.Errorf("items = %v, want an empty array", response.Items)
+	}
+}
+
+func TestSearchNewsRejectsInvalidParameters(t *testing.T) {
+	handler := newTestRouter(t, memory.NewRepository())
+
+	for _, target := range []string{
+		"/news/search",
+		"/news/search?q=%20",
+		"/news/search?q=ставка&cursor=abc",
+		"/news/search?q=ставка&limit=0",
+	} {
+		if rec := get(t, handler, target, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s: status %d, want 400", target, rec.Code)
+		}
+	}
+}
+
+func TestGetNewsByIdHandler(t *testing.T) {
+	repo := memory.NewRepository()
+	seedNews(t, repo, 1, time.Now())
+	handler := newTestRouter(t, repo)
+
+	var news entity.News
+	rec := get(t, handler, "/news/1", &news)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	for _, field := range []string{"ContentHash", "SourceKey"} {
+		if strings.Contains(rec.Body.String(), field) {
+			t.Errorf("response exposes internal field %s: %s", field, rec.Body.String())
+		}
+	}
+	if news.ID != 1 || news.Link != "https://example.com/news/1" {
+		t.Errorf("news = %+v, want news 1", news)
+	}
+
+	for target, status := range map[string]int{
+		"/news/2":           http.StatusNotFound,
+		"/news/0":           http.StatusBadRequest,
+		"/news/2/revisions": http.StatusNotFound,
+		"/news/0/revisions": http.StatusBadRequest,
+	} {
+		rec := get(t, handler, target, nil)
+		if rec.Code != status {
+			t.Errorf("GET %s: status %d, want %d", target, rec.Code, status)
+			continue
+		}
+		var response errorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil || response.Error == "" {
+			t.Errorf("GET %s: body %q is not a JSON error", target, rec.Body.String())
+		}
+		if contentType := rec.Header().Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("GET %s: Content-Type %q, want application/json", target, contentType)
+		}
+	}
+}
+
+func TestAdminEndpointsReturnEmptyLists(t *testing.T) {
+	handler := newTestRouter(t, memory.NewRepository())
+
+	for _, target := range []string{"/admin/runs", "/admin/failures"} {
+		rec := get(t, handler, target, nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s: status %d, want 200", target, rec.Code)
+			continue
+		}
+		if body := rec.Body.String(); body != "{\"items\":[]}\n" {
+			t.Errorf("GET %s: body = %q, want an empty items array", target, body)
+		}
+	}
+}
+
+func TestStatusHandler(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sources := []config.SourceConfig{{
+		Name:     "example",
+		URLs:     []string{"https://example.com/news"},
+		Parser:   "selector",
+		Selector: config.SelectorConfig{Item: "div.item"},
+	}}
+	useCase, err := usecase.NewNewsUseCase(log, memory.NewRepository(), 10,
+		config.ScraperConfig{MaxConcurrency: 1, SourceStaleAfter: time.Hour}, sources)
+	if err != nil {
+		t.Fatalf("NewNewsUseCase: %v", err)
+	}
+	t.Cleanup(useCase.Stop)
+	router := mux.NewRouter()
+	NewHTTPHandler(useCase).RegisterRoutes(router)
+
+	var response map[string]any
+	rec := get(t, router, "/status", &response)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+
+	items, _ := response["sources"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("sources = %v, want one source", response["sources"])
+	}
+	source := items[0].(map[string]any)
+	// До первого запуска после старта известно только имя источника.
+	want := map[string]any{
+		"source":          "example",
+		"last_run_at":     nil,
+		"last_status":     "",
+		"last_success_at": nil,
+		"last_error":      "",
+		"last_error_at":   nil,
+		"pending_retry":   float64(0),
+		"queue_depth":     float64(0),
+		"stale":           false,
+	}
+	if len(source) != len(want) {
+		t.Errorf("source has fields %v, want %v", source, want)
+	}
+	for key, value := range want {
+		if got, ok := source[key]; !ok || got != value {
+			t.Errorf("%s = %v, want %v", key, got, value)
+		}
+	}
+}
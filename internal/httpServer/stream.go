@@ -0,0 +1,134 @@
+package httpServer
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	streamHeartbeatInterval = 15 * time.Second
+	streamRetry             = 3 * time.Second
+	streamBackfillBatch     = 500
+)
+
+// StreamNewsHandler отдаёт новые новости по Server-Sent Events.
+// ID события совпадает с ID новости, поэтому клиент может возобновить
+// поток с заголовком Last-Event-ID (или параметром last_event_id):
+// сначала из базы досылаются новости с большим ID, затем идут новые.
+//
+// Подписка оформляется до чтения базы, чтобы новость, сохранённая между
+// ними, не потерялась; события подписки с ID не больше последнего
+// дошедшего из базы пропускаются — их клиент уже получил.
+// Если клиент не успевает читать, брокер закрывает подписку и поток
+// завершается — клиент переподключится и дочитает пропущенное из базы.
+func (h *HTTPHandler) StreamNewsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	lastID, err := parseLastEventID(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events, cancel := h.UseCase.SubscribeNews()
+	defer cancel()
+
+	var backfill []entity.News
+	if lastID > 0 {
+		// Первую страницу читаем до ответа, чтобы ошибка базы дала 500.
+		backfill, err = h.UseCase.GetNewsAfterID(r.Context(), lastID, streamBackfillBatch)
+		if err != nil {
+			http.Error(w, "Failed to get news", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	flusher.Flush()
+
+	for len(backfill) > 0 {
+		for _, news := range backfill {
+			if err := writeNewsEvent(w, news); err != nil {
+				return
+			}
+			lastID = news.ID
+		}
+		flusher.Flush()
+
+		if len(backfill) < streamBackfillBatch {
+			break
+		}
+		backfill, err = h.UseCase.GetNewsAfterID(r.Context(), lastID, streamBackfillBatch)
+		if err != nil {
+			// Клиент переподключится с последним полученным ID.
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case news, ok := <-events:
+			if !ok {
+				return
+			}
+			if news.ID <= lastID {
+				continue
+			}
+			if err := writeNewsEvent(w, news); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeNewsEvent(w http.ResponseWriter, news entity.News) error {
+	data, err := json.Marshal(news)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: news\ndata: %s\n\n", news.ID, data)
+	return err
+}
+
+func parseLastEventID(r *http.Request) (int, error) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.Atoi(raw)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("Last-Event-ID must be a news id")
+	}
+	return id, nil
+}
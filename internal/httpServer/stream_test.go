@@ -0,0 +1,133 @@
+package httpServer
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/interfaces"
+	"AIChallengeNewsAPI/internal/repository/memory"
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamNewsResumesFromRepository(t *testing.T) {
+	repo := memory.NewRepository()
+	var news []entity.News
+	// Больше двух страниц дочитывания.
+	total := 2*streamBackfillBatch + 50
+	for i := 1; i <= total; i++ {
+		news = append(news, entity.News{
+			Title:       fmt.Sprintf("Новость %d", i),
+			Link:        fmt.Sprintf("https://example.com/news/%d", i),
+			Source:      "example.com",
+			PublishedAt: time.Now(),
+		})
+	}
+	if _, err := repo.UpsertNewsBatch(context.Background(), news); err != nil {
+		t.Fatalf("UpsertNewsBatch: %v", err)
+	}
+
+	server := httptest.NewServer(newTestRouter(t, repo))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/news/stream", nil)
+	req.Header.Set("Last-Event-ID", "10")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /news/stream: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status %d, want 200", res.StatusCode)
+	}
+
+	// Все новости после Last-Event-ID: брокер их не рассылал, они есть только в базе.
+	want := total - 10
+	var ids []string
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for len(ids) < want && scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) != want {
+		t.Fatalf("got %d events, want %d (scan error: %v)", len(ids), want, scanner.Err())
+	}
+	for i, id := range ids {
+		if id != strconv.Itoa(11+i) {
+			t.Fatalf("event %d has id %s, want %d", i, id, 11+i)
+		}
+	}
+}
+
+func TestStreamNewsRejectsInvalidLastEventID(t *testing.T) {
+	handler := newTestRouter(t, memory.NewRepository())
+
+	req := httptest.NewRequest(http.MethodGet, "/news/stream", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status %d, want 400", rec.Code)
+	}
+}
+
+// streamUseCase подменяет подписку и дочитывание из базы; остальные методы
+// use case потоку не нужны.
+type streamUseCase struct {
+	interfaces.NewsUseCase
+	events   chan entity.News
+	backfill []entity.News
+}
+
+func (uc *streamUseCase) SubscribeNews() (<-chan entity.News, func()) {
+	return uc.events, func() {}
+}
+
+func (uc *streamUseCase) GetNewsAfterID(ctx context.Context, id int, limit int) ([]entity.News, error) {
+	var news []entity.News
+	for _, item := range uc.backfill {
+		if item.ID > id && len(news) < limit {
+			news = append(news, item)
+		}
+	}
+	return news, nil
+}
+
+func TestStreamNewsSkipsEventsAlreadySent(t *testing.T) {
+	useCase := &streamUseCase{
+		events:   make(chan entity.News, 3),
+		backfill: []entity.News{{ID: 11}, {ID: 12}},
+	}
+	// Пока читалась база, подписка получила уже дочитанную 12, старую 9
+	// и новую 13; затем брокер закрывает подписку.
+	useCase.events <- entity.News{ID: 12}
+	useCase.events <- entity.News{ID: 9}
+	useCase.events <- entity.News{ID: 13}
+	close(useCase.events)
+
+	req := httptest.NewRequest(http.MethodGet, "/news/stream", nil)
+	req.Header.Set("Last-Event-ID", "10")
+	rec := httptest.NewRecorder()
+	NewHTTPHandler(useCase).StreamNewsHandler(rec, req)
+
+	var ids []string
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	if got := strings.Join(ids, ","); got != "11,12,13" {
+		t.Errorf("event ids %s, want 11,12,13", got)
+	}
+}
@@ -2,24 +2,57 @@ package interfaces
 
 import (
 	"AIChallengeNewsAPI/internal/entity"
+	"context"
+	"time"
 )
 
 type RepositoryInter interface {
-	AddNews(news entity.News) error
-	GetNewsById(id int) (*entity.News, error)
-	ContainNews(url string) (bool, error)
-	GetNewsByUrl(url string) (*entity.News, error)
-	GetLatestNews(k int) ([]entity.News, error)
+	Close() error
+	Ping(ctx context.Context) error
+	SchemaReady(ctx context.Context) (bool, error)
+	UpsertNewsBatch(ctx context.Context, news []entity.News) ([]entity.News, error)
+	GetNewsById(ctx context.Context, id int) (*entity.News, error)
+	ExistingUrls(ctx context.Context, urls []string) (map[string]struct{}, error)
+	GetLatestNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error)
+	GetNewsAfterID(ctx context.Context, id int, limit int) ([]entity.News, error)
+	SearchNews(ctx context.Context, text string, filter entity.NewsFilter) ([]entity.NewsSearchResult, error)
+	StartScrapeRun(ctx context.Context, source string, staleAfter time.Duration) (*entity.ScrapeRun, error)
+	FinishScrapeRun(ctx context.Context, run entity.ScrapeRun) error
+	GetScrapeRuns(ctx context.Context, filter entity.ScrapeRunFilter) ([]entity.ScrapeRun, error)
+	GetNewsForRecheck(ctx context.Context, filter entity.NewsRecheckFilter) ([]entity.News, error)
+	MarkNewsChecked(ctx context.Context, id int, contentHash string, checkedAt time.Time) error
+	AddNewsRevision(ctx context.Context, news entity.News, revision entity.NewsRevision) (*entity.NewsRevision, error)
+	GetNewsRevisions(ctx context.Context, newsID int) ([]entity.NewsRevision, error)
 }
 
+// Parser разбирает сайт источника. ParseNewsDigest получает и адрес
+// страницы списка pageURL: относительные ссылки разрешаются от него.
 type Parser interface {
-	ParseNewsDigest(body string) ([]entity.NewsDigest, error)
-	ParseNews(body string, newsDigest entity.NewsDigest) (*entity.News, error)
-	FetchHTML(url string) (string, error)
+	ParseNewsDigest(ctx context.Context, pageURL, body string) ([]entity.NewsDigest, error)
+	ParseNews(ctx context.Context, body string, newsDigest entity.NewsDigest) (*entity.News, error)
+	FetchHTML(ctx context.Context, url string) (string, error)
+}
+
+// DigestOnlyParser реализуют парсеры, которым для части источников не нужна
+// страница статьи: новость целиком собирается из дайджеста.
+type DigestOnlyParser interface {
+	Parser
+	FetchesArticles() bool
+	NewsFromDigest(newsDigest entity.NewsDigest) *entity.News
 }
 
 type NewsUseCase interface {
-	Start()
+	Start(ctx context.Context)
 	Stop()
-	GetLatestNews(numberNews int) ([]entity.News, error)
+	GetLatestNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error)
+	GetNewsPage(ctx context.Context, filter entity.NewsFilter) (*entity.NewsPage, error)
+	GetNewsById(ctx context.Context, id int) (*entity.News, error)
+	GetNewsRevisions(ctx context.Context, id int) ([]entity.NewsRevision, error)
+	SearchNews(ctx context.Context, text string, filter entity.NewsFilter) ([]entity.NewsSearchResult, error)
+	SubscribeNews() (<-chan entity.News, func())
+	GetNewsAfterID(ctx context.Context, id int, limit int) ([]entity.News, error)
+	GetScrapeRuns(ctx context.Context, filter entity.ScrapeRunFilter) ([]entity.ScrapeRun, error)
+	Failures() []entity.ScrapeFailure
+	CheckStorage(ctx context.Context) error
+	SourceStatuses() []entity.SourceStatus
 }
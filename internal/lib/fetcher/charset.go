@@ -0,0 +1,111 @@
+package fetcher
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+const charsetPrescanSize = 1024
+
+var (
+	// <meta charset="..."> и <meta http-equiv="Content-Type" content="...; charset=...">
+	metaCharset = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([a-z0-9_:.\-]+)`)
+	// <?xml version="1.0" encoding="..."?> у RSS/Atom
+	xmlEncoding = regexp.MustCompile(`(?i)<\?xml[^>]+encoding\s*=\s*["']([a-z0-9_:.\-]+)["']`)
+)
+
+// Text декодирует тело в UTF-8. Кодировка определяется по BOM, затем по
+// charset из Content-Type, затем по <meta charset>/XML-прологу в начале
+// документа; fallback (или UTF-8, если он пуст) используется, только когда
+// ни один признак не найден.
+func (r *Response) Text(fallback string) (string, error) {
+	enc, name, err := r.detectEncoding(fallback)
+	if err != nil {
+		return "", err
+	}
+
+	body := r.Body
+	if bom := bomLength(body); bom > 0 {
+		body = body[bom:]
+	}
+	if name == "utf-8" {
+		return string(body), nil
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return "", fmt.Errorf("decode %s body: %w", name, err)
+	}
+	return string(decoded), nil
+}
+
+func (r *Response) detectEncoding(fallback string) (encoding.Encoding, string, error) {
+	switch {
+	case bytes.HasPrefix(r.Body, []byte{0xEF, 0xBB, 0xBF}):
+		return unicode.UTF8, "utf-8", nil
+	case bytes.HasPrefix(r.Body, []byte{0xFE, 0xFF}):
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), "utf-16be", nil
+	case bytes.HasPrefix(r.Body, []byte{0xFF, 0xFE}):
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), "utf-16le", nil
+	}
+
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil {
+		if enc, name, ok := lookupEncoding(params["charset"]); ok {
+			return enc, name, nil
+		}
+	}
+
+	head := r.Body
+	if len(head) > charsetPrescanSize {
+		head = head[:charsetPrescanSize]
+	}
+	for _, re := range []*regexp.Regexp{metaCharset, xmlEncoding} {
+		if matches := re.FindSubmatch(head); matches != nil {
+			if enc, name, ok := lookupEncoding(string(matches[1])); ok {
+				return enc, name, nil
+			}
+		}
+	}
+
+	if fallback == "" {
+		fallback = "utf-8"
+	}
+	enc, name, ok := lookupEncoding(fallback)
+	if !ok {
+		return nil, "", fmt.Errorf("unknown encoding %q", fallback)
+	}
+	return enc, name, nil
+}
+
+func lookupEncoding(label string) (encoding.Encoding, string, bool) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return nil, "", false
+	}
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, "", false
+	}
+	name, err := htmlindex.Name(enc)
+	if err != nil {
+		return nil, "", false
+	}
+	return enc, name, true
+}
+
+func bomLength(body []byte) int {
+	switch {
+	case bytes.HasPrefix(body, []byte{0xEF, 0xBB, 0xBF}):
+		return 3
+	case bytes.HasPrefix(body, []byte{0xFE, 0xFF}), bytes.HasPrefix(body, []byte{0xFF, 0xFE}):
+		return 2
+	}
+	return 0
+}
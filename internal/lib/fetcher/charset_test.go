@@ -0,0 +1,136 @@
+package fetcher
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func encode(t *testing.T, enc encoding.Encoding, text string) []byte {
+	t.Helper()
+
+	encoded, err := enc.NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func TestResponseText(t *testing.T) {
+	const text = "Банк России сохранил ставку"
+	cp1251 := func(s string) []byte { return encode(t, charmap.Windows1251, s) }
+	metaHTML := `<html><head><meta charset="windows-1251"></head><body>` + text + `</body></html>`
+	utf8BOM := []byte{0xEF, 0xBB, 0xBF}
+	padding := strings.Repeat(" ", charsetPrescanSize)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		fallback    string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "utf-8 without any hint",
+			body: []byte(text),
+			want: text,
+		},
+		{
+			name:     "windows-1251 without header, configured encoding",
+			body:     cp1251(text),
+			fallback: "windows-1251",
+			want:     text,
+		},
+		{
+			name:     "windows-1251 without header, meta charset beats configured encoding",
+			body:     cp1251(metaHTML),
+			fallback: "utf-8",
+			want:     metaHTML,
+		},
+		{
+			name: "meta http-equiv",
+			body: cp1251(`<meta http-equiv="Content-Type" content="text/html; charset=windows-1251">` + text),
+			want: `<meta http-equiv="Content-Type" content="text/html; charset=windows-1251">` + text,
+		},
+		{
+			name: "xml prolog",
+			body: cp1251(`<?xml version="1.0" encoding="windows-1251"?><rss>` + text + `</rss>`),
+			want: `<?xml version="1.0" encoding="windows-1251"?><rss>` + text + `</rss>`,
+		},
+		{
+			name:        "content-type beats meta charset and configured encoding",
+			contentType: "text/html; charset=utf-8",
+			body:        []byte(metaHTML),
+			fallback:    "koi8-r",
+			want:        metaHTML,
+		},
+		{
+			name:        "content-type charset alias",
+			contentType: "text/html; charset=cp1251",
+			body:        cp1251(text),
+			want:        text,
+		},
+		{
+			name:        "unknown content-type charset falls through to meta",
+			contentType: "text/html; charset=x-unknown",
+			body:        cp1251(metaHTML),
+			want:        metaHTML,
+		},
+		{
+			name:        "koi8-r from content-type",
+			contentType: "text/html; charset=KOI8-R",
+			body:        encode(t, charmap.KOI8R, text),
+			want:        text,
+		},
+		{
+			name:        "utf-8 BOM beats content-type",
+			contentType: "text/html; charset=windows-1251",
+			body:        append(utf8BOM, text...),
+			want:        text,
+		},
+		{
+			name: "utf-16le BOM",
+			body: encode(t, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), text),
+			want: text,
+		},
+		{
+			name: "meta charset beyond the prescan window is ignored",
+			body: cp1251(padding + metaHTML),
+			want: string(cp1251(padding + metaHTML)),
+		},
+		{
+			name:     "unknown configured encoding",
+			body:     []byte(text),
+			fallback: "x-unknown",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &Response{Header: http.Header{}, Body: tt.body}
+			if tt.contentType != "" {
+				res.Header.Set("Content-Type", tt.contentType)
+			}
+
+			got, err := res.Text(tt.fallback)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Text() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Text: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Text() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package fetcher
+
+import (
+	"AIChallengeNewsAPI/internal/lib/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Options struct {
+	// Source — имя источника в метриках ошибок загрузки.
+	Source      string
+	Timeout     time.Duration
+	Retries     int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	UserAgent   string
+	Headers     map[string]string
+	MaxBodySize int64
+}
+
+type Response struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// StatusError — ответ с кодом, отличным от 200. Код доступен вызывающему,
+// чтобы различать, например, блокировку (403/429) и ошибку сервера.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("invalid status code: %d", e.StatusCode)
+}
+
+var (
+	ErrBodyTooLarge   = errors.New("response body exceeds size limit")
+	ErrInvalidRequest = errors.New("invalid request")
+)
+
+// Fetcher — общий HTTP-клиент парсеров: таймаут, повторы с экспоненциальной
+// задержкой и джиттером на сетевых ошибках, 5xx и 429, учёт Retry-After.
+type Fetcher struct {
+	log     *slog.Logger
+	client  *http.Client
+	options Options
+}
+
+func New(log *slog.Logger, options Options) *Fetcher {
+	if options.BackoffBase <= 0 {
+		options.BackoffBase = 500 * time.Millisecond
+	}
+	if options.BackoffMax <= 0 {
+		options.BackoffMax = 30 * time.Second
+	}
+
+	return &Fetcher{
+		log:     log,
+		client:  &http.Client{Timeout: options.Timeout},
+		options: options,
+	}
+}
+
+// Fetch загружает url; отмена ctx прерывает и текущий запрос, и ожидание
+// перед повтором.
+func (f *Fetcher) Fetch(ctx context.Context, url string) (*Response, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= f.options.Retries; attempt++ {
+		if attempt > 0 {
+			delay, ok := f.retryDelay(attempt, lastErr)
+			if !ok {
+				break
+			}
+			f.log.Info("retrying request", slog.String("url", url), slog.Int("attempt", attempt),
+				slog.Duration("delay", delay), slog.String("error", lastErr.Error()))
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := f.do(ctx, url)
+		if err == nil {
+			return res, nil
+		}
+		lastErr = err
+		metrics.FetchErrors.WithLabelValues(f.options.Source, errorCode(err)).Inc()
+
+		if ctx.Err() != nil || !retryable(err) {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+func (f *Fetcher) do(ctx context.Context, url string) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if f.options.UserAgent != "" {
+		req.Header.Set("User-Agent", f.options.UserAgent)
+	}
+	for key, value := range f.options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", url, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+		return nil, &retryAfterError{
+			StatusError: &StatusError{URL: url, StatusCode: res.StatusCode},
+			retryAfter:  parseRetryAfter(res.Header.Get("Retry-After")),
+		}
+	}
+
+	var reader io.Reader = res.Body
+	if f.options.MaxBodySize > 0 {
+		reader = io.LimitReader(res.Body, f.options.MaxBodySize+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error read body: %w", err)
+	}
+	if f.options.MaxBodySize > 0 && int64(len(body)) > f.options.MaxBodySize {
+		return nil, fmt.Errorf("%s: %w", url, ErrBodyTooLarge)
+	}
+
+	return &Response{
+		URL:        url,
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       body,
+	}, nil
+}
+
+// retryDelay возвращает задержку перед попыткой attempt. Если сервер просит
+// подождать дольше BackoffMax, повторять бессмысленно — ok будет false.
+func (f *Fetcher) retryDelay(attempt int, lastErr error) (time.Duration, bool) {
+	var statusErr *retryAfterError
+	if errors.As(lastErr, &statusErr) && statusErr.retryAfter > 0 {
+		return statusErr.retryAfter, statusErr.retryAfter <= f.options.BackoffMax
+	}
+
+	backoff := f.options.BackoffBase << (attempt - 1)
+	if backoff <= 0 || backoff > f.options.BackoffMax {
+		backoff = f.options.BackoffMax
+	}
+	// Джиттер: задержка равномерно в [backoff/2, backoff], чтобы источники
+	// не повторяли запросы синхронно.
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1)), true
+}
+
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	return !errors.Is(err, ErrBodyTooLarge) && !errors.Is(err, ErrInvalidRequest)
+}
+
+// errorCode — значение метки code в метрике ошибок загрузки: HTTP-код ответа
+// или вид ошибки, если ответа не было.
+func errorCode(err error) string {
+	var statusErr *StatusError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &statusErr):
+		return strconv.Itoa(statusErr.StatusCode)
+	case errors.Is(err, ErrBodyTooLarge):
+		return "body_too_large"
+	case errors.Is(err, ErrInvalidRequest):
+		return "invalid_request"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	default:
+		return "network"
+	}
+}
+
+type retryAfterError struct {
+	*StatusError
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.StatusError
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(at); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
@@ -0,0 +1,244 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer отвечает по очереди ответами из responses, повторяя последний.
+type testServer struct {
+	mu        sync.Mutex
+	responses []testResponse
+	requests  []*http.Request
+}
+
+type testResponse struct {
+	status     int
+	retryAfter string
+	body       string
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	response := s.responses[min(len(s.requests), len(s.responses)-1)]
+	s.requests = append(s.requests, r)
+	s.mu.Unlock()
+
+	if response.retryAfter != "" {
+		w.Header().Set("Retry-After", response.retryAfter)
+	}
+	w.WriteHeader(response.status)
+	io.WriteString(w, response.body)
+}
+
+func (s *testServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func newTestFetcher(options Options) *Fetcher {
+	options.Source = "test"
+	if options.BackoffBase == 0 {
+		options.BackoffBase = time.Millisecond
+	}
+	if options.BackoffMax == 0 {
+		options.BackoffMax = 10 * time.Millisecond
+	}
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), options)
+}
+
+func TestFetchRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      Options
+		responses    []testResponse
+		wantRequests int
+		wantStatus   int
+		wantBody     string
+	}{
+		{
+			name:         "5xx then success",
+			options:      Options{Retries: 3},
+			responses:    []testResponse{{status: 503}, {status: 502}, {status: 200, body: "ok"}},
+			wantRequests: 3,
+			wantBody:     "ok",
+		},
+		{
+			name:         "retries exhausted",
+			options:      Options{Retries: 2},
+			responses:    []testResponse{{status: 500}},
+			wantRequests: 3,
+			wantStatus:   500,
+		},
+		{
+			name:         "client error is not retried",
+			options:      Options{Retries: 3},
+			responses:    []testResponse{{status: 404}, {status: 200, body: "ok"}},
+			wantRequests: 1,
+			wantStatus:   404,
+		},
+		{
+			name:         "retry-after longer than backoff max",
+			options:      Options{Retries: 3},
+			responses:    []testResponse{{status: 429, retryAfter: "120"}, {status: 200, body: "ok"}},
+			wantRequests: 1,
+			wantStatus:   429,
+		},
+		{
+			name:         "no retries configured",
+			options:      Options{},
+			responses:    []testResponse{{status: 503}, {status: 200, body: "ok"}},
+			wantRequests: 1,
+			wantStatus:   503,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := &testServer{responses: tt.responses}
+			server := httptest.NewServer(site)
+			defer server.Close()
+
+			res, err := newTestFetcher(tt.options).Fetch(context.Background(), server.URL)
+
+			if got := site.requestCount(); got != tt.wantRequests {
+				t.Errorf("server got %d requests, want %d", got, tt.wantRequests)
+			}
+			if tt.wantStatus != 0 {
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus {
+					t.Fatalf("Fetch: got %v, want StatusError %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+			if string(res.Body) != tt.wantBody {
+				t.Errorf("body %q, want %q", res.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestFetchWaitsRetryAfter(t *testing.T) {
+	site := &testServer{responses: []testResponse{{status: 429, retryAfter: "1"}, {status: 200, body: "ok"}}}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	started := time.Now()
+	res, err := newTestFetcher(Options{Retries: 1, BackoffMax: 2 * time.Second}).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if string(res.Body) != "ok" {
+		t.Errorf("body %q, want %q", res.Body, "ok")
+	}
+	// Без Retry-After задержка не превысила бы BackoffBase (1ms).
+	if elapsed := time.Since(started); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the Retry-After of 1s", elapsed)
+	}
+}
+
+func TestFetchCancelDuringBackoff(t *testing.T) {
+	site := &testServer{responses: []testResponse{{status: 503}}}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	fetcher := newTestFetcher(Options{Retries: 3, BackoffBase: time.Minute, BackoffMax: time.Minute})
+	if _, err := fetcher.Fetch(ctx, server.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Fetch: got %v, want context.DeadlineExceeded", err)
+	}
+	if got := site.requestCount(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+func TestFetchMaxBodySize(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+	}{
+		{name: "at the limit", body: strings.Repeat("a", 16)},
+		{name: "over the limit", body: strings.Repeat("a", 17), wantErr: ErrBodyTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := &testServer{responses: []testResponse{{status: 200, body: tt.body}}}
+			server := httptest.NewServer(site)
+			defer server.Close()
+
+			res, err := newTestFetcher(Options{Retries: 3, MaxBodySize: 16}).Fetch(context.Background(), server.URL)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Fetch: got %v, want %v", err, tt.wantErr)
+			}
+			// Слишком большой ответ не повторяется: он не станет меньше.
+			if got := site.requestCount(); got != 1 {
+				t.Errorf("server got %d requests, want 1", got)
+			}
+			if tt.wantErr == nil && string(res.Body) != tt.body {
+				t.Errorf("body %q, want %q", res.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestFetchSendsHeaders(t *testing.T) {
+	site := &testServer{responses: []testResponse{{status: 200}}}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	fetcher := newTestFetcher(Options{UserAgent: "news-bot/1.0", Headers: map[string]string{"Accept-Language": "ru-RU"}})
+	if _, err := fetcher.Fetch(context.Background(), server.URL); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	req := site.requests[0]
+	if got := req.Header.Get("User-Agent"); got != "news-bot/1.0" {
+		t.Errorf("User-Agent %q, want %q", got, "news-bot/1.0")
+	}
+	if got := req.Header.Get("Accept-Language"); got != "ru-RU" {
+		t.Errorf("Accept-Language %q, want %q", got, "ru-RU")
+	}
+}
+
+func TestFetchInvalidURL(t *testing.T) {
+	if _, err := newTestFetcher(Options{Retries: 3}).Fetch(context.Background(), "://bad"); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Fetch: got %v, want ErrInvalidRequest", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{value: "", min: 0, max: 0},
+		{value: "5", min: 5 * time.Second, max: 5 * time.Second},
+		{value: "-1", min: 0, max: 0},
+		{value: "soon", min: 0, max: 0},
+		{value: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), min: 58 * time.Second, max: time.Minute},
+		{value: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+			t.Errorf("parseRetryAfter(%q) = %v, want between %v and %v", tt.value, got, tt.min, tt.max)
+		}
+	}
+}
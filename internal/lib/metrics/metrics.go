@@ -0,0 +1,118 @@
+// Package metrics объявляет метрики Prometheus приложения. Все они
+// регистрируются в стандартном реестре и отдаются обработчиком /metrics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "news"
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP API requests by route, method and status code.",
+	}, []string{"route", "method", "code"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP API request latency by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	ScrapeRunDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scrape_run_duration_seconds",
+		Help:      "Duration of a source scrape run by final status.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800},
+	}, []string{"source", "status"})
+
+	ScrapeDigests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrape_digests_total",
+		Help:      "News items found on listing pages.",
+	}, []string{"source"})
+
+	ScrapeInserted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrape_inserted_total",
+		Help:      "News articles inserted into the database.",
+	}, []string{"source"})
+
+	ScrapeFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrape_failed_total",
+		Help:      "News articles that could not be fetched, parsed or stored.",
+	}, []string{"source"})
+
+	// ScrapeLastRunDigests позволяет заметить, что парсер перестал находить
+	// новости (например, после смены вёрстки сайта), без rate() по счётчику.
+	ScrapeLastRunDigests = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "scrape_last_run_digests",
+		Help:      "News items found on listing pages during the last finished run.",
+	}, []string{"source"})
+
+	ScrapeLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "scrape_last_success_timestamp_seconds",
+		Help:      "Unix time of the last succeeded or partial run.",
+	}, []string{"source"})
+
+	// ScrapeRunFinishErrors — запуски, итог которых не удалось записать:
+	// они числятся «running» до истечения SCRAPE_RUN_STALE_AFTER.
+	ScrapeRunFinishErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrape_run_finish_errors_total",
+		Help:      "Scrape runs whose result could not be saved after all retries.",
+	}, []string{"source"})
+
+	ScrapeRechecked = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrape_rechecked_total",
+		Help:      "Published articles fetched again to detect changes.",
+	}, []string{"source"})
+
+	ScrapeRevisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrape_revisions_total",
+		Help:      "Changes detected in already published articles.",
+	}, []string{"source"})
+
+	ScrapeQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "scrape_queue_depth",
+		Help:      "Articles waiting to be fetched.",
+	}, []string{"source"})
+
+	FetchErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetch_errors_total",
+		Help:      "Failed HTTP fetch attempts by source and status code (or error kind).",
+	}, []string{"source", "code"})
+
+	ParseErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "parse_errors_total",
+		Help:      "Parser errors by source, parser type and stage (digest or article).",
+	}, []string{"source", "parser", "stage"})
+
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Repository query latency by query name.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"query"})
+)
+
+// ObserveQuery записывает длительность запроса к базе, начатого в started:
+//
+//	defer metrics.ObserveQuery("get_news_by_id", time.Now())
+func ObserveQuery(query string, started time.Time) {
+	DBQueryDuration.WithLabelValues(query).Observe(time.Since(started).Seconds())
+}
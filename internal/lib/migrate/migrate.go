@@ -0,0 +1,315 @@
+// Package migrate применяет версионированные SQL-миграции, встроенные
+// в бинарник. Отличия СУБД (блокировка, типы, проверка наличия таблицы)
+// описывает Dialect, поэтому один движок обслуживает Postgres и SQLite.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+// Dialect — то, чем СУБД отличаются для движка миграций.
+type Dialect struct {
+	// Lock берёт межпроцессную блокировку на conn, чтобы реплики не применяли
+	// миграции одновременно, и возвращает функцию её снятия.
+	Lock func(ctx context.Context, conn *sql.Conn) (unlock func(), err error)
+	// TableExistsQuery возвращает одну булеву колонку: создана ли schema_migrations.
+	TableExistsQuery string
+	// TimestampType — тип колонки applied_at.
+	TimestampType string
+}
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status — состояние одной миграции для команды migrate status.
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+type applied struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// New читает миграции из каталога dir в fsys: файлы NNNN_name.up.sql
+// и NNNN_name.down.sql. У каждой версии должен быть up-файл; down-файл
+// необязателен.
+func New(db *sql.DB, dialect Dialect, fsys fs.FS, dir string) (*Migrator, error) {
+	migrations, err := load(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		matches := fileName.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, _ := strconv.Atoi(matches[1])
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		}
+		if m.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has different names: %q and %q", version, m.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			m.Up = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up применяет все ещё не применённые миграции, каждую в своей транзакции.
+// Перед этим сверяет контрольные суммы уже применённых: изменённый после
+// применения файл — ошибка, а не тихое расхождение схем.
+func (m *Migrator) Up(ctx context.Context, log *slog.Logger) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			log.Info("applying migration", slog.Int("version", migration.Version), slog.String("name", migration.Name))
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, $4)",
+					migration.Version, migration.Name, migration.Checksum, time.Now().UTC())
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Down откатывает steps последних применённых миграций.
+func (m *Migrator) Down(ctx context.Context, log *slog.Logger, steps int) error {
+	byVersion := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int, 0, len(done))
+		for version := range done {
+			versions = append(versions, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for _, version := range versions[:min(steps, len(versions))] {
+			migration, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("migration %d is applied but not known to this build", version)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be rolled back: no down file", migration.Version, migration.Name)
+			}
+			log.Info("rolling back migration", slog.Int("version", migration.Version), slog.String("name", migration.Name))
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("rollback %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Status возвращает все известные миграции с отметкой о применении.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	done, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if a, ok := done[migration.Version]; ok {
+			status.Applied = true
+			appliedAt := a.appliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Ready сообщает, применены ли все миграции, встроенные в эту сборку.
+func (m *Migrator) Ready(ctx context.Context) (bool, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, status := range statuses {
+		if !status.Applied {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// withLock выполняет fn на отдельном соединении под блокировкой диалекта:
+// блокировка и миграции должны идти в одной сессии.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	unlock, err := m.dialect.Lock(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer unlock()
+
+	_, err = conn.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at %s NOT NULL)`, m.dialect.TimestampType))
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// applied читает применённые миграции и сверяет их контрольные суммы.
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]applied, error) {
+	done := make(map[int]applied)
+
+	var exists bool
+	if err := conn.QueryRowContext(ctx, m.dialect.TableExistsQuery).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return done, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var a applied
+		if err := rows.Scan(&version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		a, ok := done[migration.Version]
+		if ok && a.checksum != migration.Checksum {
+			return nil, fmt.Errorf("%w: %d_%s was changed after it had been applied",
+				ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+	return done, nil
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
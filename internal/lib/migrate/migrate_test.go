@@ -0,0 +1,223 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "modernc.org/sqlite"
+)
+
+// testDialect — SQLite без межпроцессной блокировки: в тестах процесс один.
+var testDialect = Dialect{
+	Lock:             func(ctx context.Context, conn *sql.Conn) (func(), error) { return func() {}, nil },
+	TableExistsQuery: "SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')",
+	TimestampType:    "DATETIME",
+}
+
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"migrations/0001_news.up.sql":     {Data: []byte("CREATE TABLE news (id INTEGER PRIMARY KEY, title TEXT NOT NULL)")},
+		"migrations/0001_news.down.sql":   {Data: []byte("DROP TABLE news")},
+		"migrations/0002_source.up.sql":   {Data: []byte("ALTER TABLE news ADD COLUMN source TEXT")},
+		"migrations/0002_source.down.sql": {Data: []byte("ALTER TABLE news DROP COLUMN source")},
+	}
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestMigrator(t *testing.T, db *sql.DB, fsys fstest.MapFS) *Migrator {
+	t.Helper()
+
+	m, err := New(db, testDialect, fsys, "migrations")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return m
+}
+
+func appliedVersions(t *testing.T, m *Migrator) []int {
+	t.Helper()
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	var versions []int
+	for _, status := range statuses {
+		if status.Applied {
+			versions = append(versions, status.Version)
+		}
+	}
+	return versions
+}
+
+func TestUpIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := openTestDB(t)
+	m := newTestMigrator(t, db, testMigrations())
+
+	if ready, err := m.Ready(ctx); err != nil || ready {
+		t.Fatalf("Ready before Up = %v, %v; want false", ready, err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := m.Up(ctx, log); err != nil {
+			t.Fatalf("Up #%d: %v", i+1, err)
+		}
+	}
+	if ready, err := m.Ready(ctx); err != nil || !ready {
+		t.Fatalf("Ready after Up = %v, %v; want true", ready, err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("schema_migrations has %d rows, want 2", count)
+	}
+	if _, err := db.Exec("INSERT INTO news (title, source) VALUES ('a', 'b')"); err != nil {
+		t.Errorf("schema was not migrated: %v", err)
+	}
+}
+
+func TestUpAppliesNewMigrations(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := openTestDB(t)
+
+	fsys := testMigrations()
+	delete(fsys, "migrations/0002_source.up.sql")
+	delete(fsys, "migrations/0002_source.down.sql")
+	if err := newTestMigrator(t, db, fsys).Up(ctx, log); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	m := newTestMigrator(t, db, testMigrations())
+	if ready, _ := m.Ready(ctx); ready {
+		t.Fatal("Ready with an unapplied migration")
+	}
+	if err := m.Up(ctx, log); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if got := appliedVersions(t, m); len(got) != 2 {
+		t.Errorf("applied %v, want [1 2]", got)
+	}
+}
+
+func TestUpRejectsChangedMigration(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := openTestDB(t)
+
+	if err := newTestMigrator(t, db, testMigrations()).Up(ctx, log); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	changed := testMigrations()
+	changed["migrations/0001_news.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE news (id INTEGER PRIMARY KEY, title TEXT)")}
+	m := newTestMigrator(t, db, changed)
+
+	if err := m.Up(ctx, log); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Up: got %v, want ErrChecksumMismatch", err)
+	}
+	if _, err := m.Ready(ctx); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Ready: got %v, want ErrChecksumMismatch", err)
+	}
+}
+
+func TestUpRollsBackFailedMigration(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := openTestDB(t)
+
+	fsys := testMigrations()
+	fsys["migrations/0002_source.up.sql"] = &fstest.MapFile{Data: []byte("ALTER TABLE missing ADD COLUMN source TEXT")}
+	m := newTestMigrator(t, db, fsys)
+
+	if err := m.Up(ctx, log); err == nil {
+		t.Fatal("Up succeeded with a broken migration")
+	}
+	// Первая миграция применена, сломанная не записана как применённая.
+	if got := appliedVersions(t, m); len(got) != 1 || got[0] != 1 {
+		t.Errorf("applied %v, want [1]", got)
+	}
+}
+
+func TestDown(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := openTestDB(t)
+	m := newTestMigrator(t, db, testMigrations())
+
+	if err := m.Up(ctx, log); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := m.Down(ctx, log, 1); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if got := appliedVersions(t, m); len(got) != 1 || got[0] != 1 {
+		t.Errorf("applied after Down(1) %v, want [1]", got)
+	}
+	if err := m.Down(ctx, log, 10); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if got := appliedVersions(t, m); len(got) != 0 {
+		t.Errorf("applied after Down(10) %v, want none", got)
+	}
+	if err := m.Up(ctx, log); err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+
+	fsys := testMigrations()
+	delete(fsys, "migrations/0002_source.down.sql")
+	if err := newTestMigrator(t, db, fsys).Down(ctx, log, 1); err == nil {
+		t.Error("Down succeeded for a migration without a down file")
+	}
+}
+
+func TestNewRejectsInvalidMigrations(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{
+			name:  "invalid file name",
+			files: fstest.MapFS{"migrations/news.sql": {Data: []byte("SELECT 1")}},
+		},
+		{
+			name:  "no up file",
+			files: fstest.MapFS{"migrations/0001_news.down.sql": {Data: []byte("DROP TABLE news")}},
+		},
+		{
+			name: "different names for one version",
+			files: fstest.MapFS{
+				"migrations/0001_news.up.sql":    {Data: []byte("CREATE TABLE news (id INTEGER)")},
+				"migrations/0001_other.down.sql": {Data: []byte("DROP TABLE news")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(nil, testDialect, tt.files, "migrations"); err == nil {
+				t.Error("New succeeded, want an error")
+			}
+		})
+	}
+}
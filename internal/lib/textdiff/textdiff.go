@@ -0,0 +1,156 @@
+// Package textdiff строит построчный diff двух текстов в формате unified diff.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// contextLines — сколько неизменённых строк показывать вокруг изменения.
+	contextLines = 3
+	// maxCells ограничивает таблицу LCS: для очень длинных текстов с
+	// изменениями повсюду diff вырождается в «удалить всё, добавить всё».
+	maxCells = 4_000_000
+)
+
+type op struct {
+	kind byte // ' ', '-' или '+'
+	text string
+}
+
+// Unified возвращает изменения от before к after в виде ханков
+// "@@ -a,b +c,d @@". Для одинаковых текстов возвращает пустую строку.
+func Unified(before, after string) string {
+	ops := diffLines(splitLines(before), splitLines(after))
+
+	var changes []int
+	for i, o := range ops {
+		if o.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	// Позиции строк перед каждой операцией — для заголовков ханков.
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	for i, o := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if o.kind != '+' {
+			aPos[i+1]++
+		}
+		if o.kind != '-' {
+			bPos[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(changes); {
+		start := max(0, changes[i]-contextLines)
+		end := min(len(ops), changes[i]+1+contextLines)
+		for i++; i < len(changes) && changes[i]-contextLines <= end; i++ {
+			end = min(len(ops), changes[i]+1+contextLines)
+		}
+
+		aCount, bCount := aPos[end]-aPos[start], bPos[end]-bPos[start]
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(aPos[start], aCount), hunkRange(bPos[start], bCount))
+		for _, o := range ops[start:end] {
+			b.WriteByte(o.kind)
+			b.WriteString(o.text)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+func hunkRange(pos, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffLines находит наибольшую общую подпоследовательность строк. Общие
+// начало и конец отрезаются заранее: правки статьи обычно локальны.
+func diffLines(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{' ', line})
+	}
+	ops = append(ops, lcs(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{' ', line})
+	}
+	return ops
+}
+
+func lcs(a, b []string) []op {
+	var ops []op
+	if len(a)*len(b) > maxCells {
+		for _, line := range a {
+			ops = append(ops, op{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, op{'+', line})
+		}
+		return ops
+	}
+
+	// length[i][j] — длина LCS для a[i:] и b[j:].
+	length := make([][]int, len(a)+1)
+	for i := range length {
+		length[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				length[i][j] = length[i+1][j+1] + 1
+			} else {
+				length[i][j] = max(length[i+1][j], length[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case length[i+1][j] >= length[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
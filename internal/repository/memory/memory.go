@@ -0,0 +1,449 @@
+// Package memory — реализация interfaces.RepositoryInter в памяти процесса.
+// Подходит для локального запуска без PostgreSQL и для тестов; данные
+// теряются при перезапуске.
+package memory
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrClosed = errors.New("repository is closed")
+
+// Repository хранит новости и историю запусков под одним RWMutex.
+// Методы возвращают копии, поэтому вызывающий может менять результат.
+type Repository struct {
+	mu        sync.RWMutex
+	closed    bool
+	news      map[int]entity.News
+	urls      map[string]int
+	nextID    int
+	checkedAt map[int]time.Time
+	runs      []entity.ScrapeRun
+	nextRunID int
+
+	revisions      []entity.NewsRevision
+	nextRevisionID int
+}
+
+func NewRepository() *Repository {
+	return &Repository{
+		news:      make(map[int]entity.News),
+		urls:      make(map[string]int),
+		checkedAt: make(map[int]time.Time),
+	}
+}
+
+func (repo *Repository) Close() error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.closed = true
+	return nil
+}
+
+func (repo *Repository) Ping(ctx context.Context) error {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	return repo.check(ctx)
+}
+
+// SchemaReady всегда true: схемы, которую надо мигрировать, здесь нет.
+func (repo *Repository) SchemaReady(ctx context.Context) (bool, error) {
+	if err := repo.Ping(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UpsertNewsBatch добавляет новости с ещё неизвестными url и возвращает
+// вставленные — в исходном порядке и с заполненным ID, как и версия для Postgres.
+func (repo *Repository) UpsertNewsBatch(ctx context.Context, news []entity.News) ([]entity.News, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+
+	var inserted []entity.News
+	for _, item := range news {
+		if _, ok := repo.urls[item.Link]; ok {
+			continue
+		}
+		item.ID = repo.insert(item)
+		inserted = append(inserted, item)
+	}
+	return inserted, nil
+}
+
+func (repo *Repository) GetNewsById(ctx context.Context, id int) (*entity.News, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+	news, ok := repo.news[id]
+	if !ok {
+		return nil, entity.ErrNewsNotFound
+	}
+	return &news, nil
+}
+
+func (repo *Repository) ExistingUrls(ctx context.Context, urls []string) (map[string]struct{}, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+	existing := make(map[string]struct{})
+	for _, url := range urls {
+		if _, ok := repo.urls[url]; ok {
+			existing[url] = struct{}{}
+		}
+	}
+	return existing, nil
+}
+
+func (repo *Repository) GetLatestNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+
+	var newsList []entity.News
+	for _, news := range repo.news {
+		if !matchFilter(news, filter) {
+			continue
+		}
+		if filter.After != nil && !before(news, filter.After.PublishedAt, filter.After.ID) {
+			continue
+		}
+		newsList = append(newsList, news)
+	}
+	sortNewest(newsList)
+
+	if filter.Limit > 0 && len(newsList) > filter.Limit {
+		newsList = newsList[:filter.Limit]
+	}
+	return newsList, nil
+}
+
+// GetNewsAfterID возвращает до limit новостей с ID больше id в порядке ID.
+func (repo *Repository) GetNewsAfterID(ctx context.Context, id int, limit int) ([]entity.News, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+
+	var newsList []entity.News
+	for newsID, news := range repo.news {
+		if newsID > id {
+			newsList = append(newsList, news)
+		}
+	}
+	sort.Slice(newsList, func(i, j int) bool { return newsList[i].ID < newsList[j].ID })
+
+	if limit > 0 && len(newsList) > limit {
+		newsList = newsList[:limit]
+	}
+	return newsList, nil
+}
+
+// SearchNews — упрощённый аналог websearch_to_tsquery без морфологии:
+// слова ищутся как подстроки без учёта регистра, фразы в кавычках — целиком,
+// слова с минусом исключают новость. Совпадение в заголовке весит больше,
+// чем в тексте.
+func (repo *Repository) SearchNews(ctx context.Context, text string, filter entity.NewsFilter) ([]entity.NewsSearchResult, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+
+	query := parseQuery(text)
+	if len(query.include) == 0 {
+		return nil, nil
+	}
+
+	var results []entity.NewsSearchResult
+	for _, news := range repo.news {
+		if !matchFilter(news, filter) {
+			continue
+		}
+		rank, ok := query.rank(news)
+		if !ok {
+			continue
+		}
+		results = append(results, entity.NewsSearchResult{
+			News:     news,
+			Rank:     rank,
+			Headline: headline(news.Text, query.include),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return before(results[j].News, results[i].PublishedAt, results[i].ID)
+	})
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		results = results[:filter.Limit]
+	}
+	return results, nil
+}
+
+// GetNewsForRecheck возвращает статьи источника, которые пора проверить на
+// изменения: сначала те, что дольше всех не проверялись.
+func (repo *Repository) GetNewsForRecheck(ctx context.Context, filter entity.NewsRecheckFilter) ([]entity.News, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+
+	var newsList []entity.News
+	for id, news := range repo.news {
+		if news.SourceKey != filter.SourceKey || news.PublishedAt.Before(filter.PublishedAfter) {
+			continue
+		}
+		if !repo.checkedAt[id].Before(filter.CheckedBefore) {
+			continue
+		}
+		newsList = append(newsList, news)
+	}
+	sort.Slice(newsList, func(i, j int) bool {
+		ci, cj := repo.checkedAt[newsList[i].ID], repo.checkedAt[newsList[j].ID]
+		if !ci.Equal(cj) {
+			return ci.Before(cj)
+		}
+		return newsList[i].ID < newsList[j].ID
+	})
+	if filter.Limit > 0 && len(newsList) > filter.Limit {
+		newsList = newsList[:filter.Limit]
+	}
+	return newsList, nil
+}
+
+func (repo *Repository) MarkNewsChecked(ctx context.Context, id int, contentHash string, checkedAt time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if err := repo.check(ctx); err != nil {
+		return err
+	}
+	news, ok := repo.news[id]
+	if !ok {
+		return nil
+	}
+	news.ContentHash = contentHash
+	repo.news[id] = news
+	repo.checkedAt[id] = checkedAt
+	return nil
+}
+
+// AddNewsRevision обновляет статью до новой версии и сохраняет изменение.
+func (repo *Repository) AddNewsRevision(ctx context.Context, news entity.News, revision entity.NewsRevision) (*entity.NewsRevision, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+	stored, ok := repo.news[revision.NewsID]
+	if !ok {
+		return nil, entity.ErrNewsNotFound
+	}
+	stored.Title = news.Title
+	stored.Text = news.Text
+	stored.ContentHash = revision.ContentHash
+	repo.news[stored.ID] = stored
+	repo.checkedAt[stored.ID] = revision.DetectedAt
+
+	repo.nextRevisionID++
+	revision.ID = repo.nextRevisionID
+	repo.revisions = append(repo.revisions, revision)
+	return &revision, nil
+}
+
+// GetNewsRevisions возвращает изменения статьи, новые первыми.
+func (repo *Repository) GetNewsRevisions(ctx context.Context, newsID int) ([]entity.NewsRevision, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+
+	var revisions []entity.NewsRevision
+	for i := len(repo.revisions) - 1; i >= 0; i-- {
+		if repo.revisions[i].NewsID == newsID {
+			revisions = append(revisions, repo.revisions[i])
+		}
+	}
+	return revisions, nil
+}
+
+// StartScrapeRun повторяет семантику Postgres: зависшие дольше staleAfter
+// запуски помечаются abandoned, а незавершённый запуск источника даёт
+// entity.ErrRunInProgress.
+func (repo *Repository) StartScrapeRun(ctx context.Context, source string, staleAfter time.Duration) (*entity.ScrapeRun, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range repo.runs {
+		run := &repo.runs[i]
+		if run.Source != source || run.FinishedAt != nil {
+			continue
+		}
+		if run.StartedAt.Before(now.Add(-staleAfter)) {
+			finishedAt := now
+			run.Status = entity.RunStatusAbandoned
+			run.FinishedAt = &finishedAt
+			run.Error = "run did not finish"
+			continue
+		}
+		return nil, entity.ErrRunInProgress
+	}
+
+	repo.nextRunID++
+	run := entity.ScrapeRun{ID: repo.nextRunID, Source: source, Status: entity.RunStatusRunning, StartedAt: now}
+	repo.runs = append(repo.runs, run)
+	return &run, nil
+}
+
+func (repo *Repository) FinishScrapeRun(ctx context.Context, run entity.ScrapeRun) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if err := repo.check(ctx); err != nil {
+		return err
+	}
+	for i := range repo.runs {
+		if repo.runs[i].ID != run.ID {
+			continue
+		}
+		stored := &repo.runs[i]
+		stored.Status = run.Status
+		stored.FinishedAt = copyTime(run.FinishedAt)
+		stored.Digests = run.Digests
+		stored.Fetched = run.Fetched
+		stored.Inserted = run.Inserted
+		stored.Failed = run.Failed
+		stored.Error = run.Error
+		return nil
+	}
+	return nil
+}
+
+func (repo *Repository) GetScrapeRuns(ctx context.Context, filter entity.ScrapeRunFilter) ([]entity.ScrapeRun, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if err := repo.check(ctx); err != nil {
+		return nil, err
+	}
+
+	var runs []entity.ScrapeRun
+	for _, run := range repo.runs {
+		if filter.Source != "" && run.Source != filter.Source {
+			continue
+		}
+		run.FinishedAt = copyTime(run.FinishedAt)
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].StartedAt.Equal(runs[j].StartedAt) {
+			return runs[i].StartedAt.After(runs[j].StartedAt)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+	if filter.Limit > 0 && len(runs) > filter.Limit {
+		runs = runs[:filter.Limit]
+	}
+	return runs, nil
+}
+
+// check вызывается под блокировкой.
+func (repo *Repository) check(ctx context.Context) error {
+	if repo.closed {
+		return ErrClosed
+	}
+	return ctx.Err()
+}
+
+// insert вызывается под блокировкой записи.
+func (repo *Repository) insert(news entity.News) int {
+	repo.nextID++
+	news.ID = repo.nextID
+	repo.news[news.ID] = news
+	repo.urls[news.Link] = news.ID
+	repo.checkedAt[news.ID] = time.Now()
+	return news.ID
+}
+
+func matchFilter(news entity.News, filter entity.NewsFilter) bool {
+	if len(filter.Sources) > 0 && !containsFold(filter.Sources, news.Source) {
+		return false
+	}
+	if len(filter.Categories) > 0 && !containsFold(filter.Categories, news.Category) {
+		return false
+	}
+	if !filter.From.IsZero() && news.PublishedAt.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && news.PublishedAt.After(filter.To) {
+		return false
+	}
+	return true
+}
+
+// before сообщает, идёт ли news после курсора (publishedAt, id) в порядке
+// «сначала новые» — то же условие (published_at, id) < ($1, $2), что в SQL.
+func before(news entity.News, publishedAt time.Time, id int) bool {
+	if news.PublishedAt.Equal(publishedAt) {
+		return news.ID < id
+	}
+	return news.PublishedAt.Before(publishedAt)
+}
+
+func sortNewest(newsList []entity.News) {
+	sort.Slice(newsList, func(i, j int) bool {
+		return before(newsList[j], newsList[i].PublishedAt, newsList[i].ID)
+	})
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	titleWeight     = 1.0
+	textWeight      = 0.4
+	headlineContext = 80
+)
+
+type searchQuery struct {
+	include []string
+	exclude []string
+}
+
+// parseQuery разбирает запрос в духе websearch_to_tsquery: "фраза в кавычках",
+// -исключение, остальные слова обязательны.
+func parseQuery(text string) searchQuery {
+	var query searchQuery
+	text = strings.ToLower(text)
+
+	for len(text) > 0 {
+		text = strings.TrimLeft(text, " \t\n")
+		if text == "" {
+			break
+		}
+
+		negate := false
+		if text[0] == '-' {
+			negate = true
+			text = text[1:]
+		}
+
+		var term string
+		if strings.HasPrefix(text, `"`) {
+			end := strings.Index(text[1:], `"`)
+			if end < 0 {
+				term, text = text[1:], ""
+			} else {
+				term, text = text[1:end+1], text[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(text, " \t\n")
+			if end < 0 {
+				term, text = text, ""
+			} else {
+				term, text = text[:end], text[end:]
+			}
+		}
+
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		if negate {
+			query.exclude = append(query.exclude, term)
+		} else {
+			query.include = append(query.include, term)
+		}
+	}
+	return query
+}
+
+// rank возвращает вес совпадения; ok = false, если новость не подходит.
+func (q searchQuery) rank(news entity.News) (float64, bool) {
+	title := strings.ToLower(news.Title)
+	text := strings.ToLower(news.Text)
+
+	for _, term := range q.exclude {
+		if strings.Contains(title, term) || strings.Contains(text, term) {
+			return 0, false
+		}
+	}
+
+	var rank float64
+	for _, term := range q.include {
+		inTitle := strings.Count(title, term)
+		inText := strings.Count(text, term)
+		if inTitle == 0 && inText == 0 {
+			return 0, false
+		}
+		rank += titleWeight*float64(inTitle) + textWeight*float64(inText)
+	}
+	return rank, true
+}
+
+// headline вырезает фрагмент текста вокруг первого совпадения и выделяет
+// найденные слова тегами <b>, как ts_headline.
+func headline(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	first := -1
+	for _, term := range terms {
+		if i := strings.Index(lower, term); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+	if first < 0 || len(lower) != len(text) {
+		// Смена регистра изменила длину в байтах — индексы не совпадут
+		// с исходным текстом, отдаём начало текста без подсветки.
+		return truncate(text, 2*headlineContext)
+	}
+
+	// Границы фрагмента сдвигаются к пробелам, чтобы не резать слова.
+	start := max(0, first-headlineContext)
+	if start > 0 {
+		if space := strings.IndexByte(text[start:first], ' '); space >= 0 {
+			start += space + 1
+		}
+	}
+	end := min(len(text), first+2*headlineContext)
+	if end < len(text) {
+		if space := strings.LastIndexByte(text[first:end], ' '); space > 0 {
+			end = first + space
+		}
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	fragment, lowerFragment := text[start:end], lower[start:end]
+
+	var b strings.Builder
+	for i := 0; i < len(fragment); {
+		matched := ""
+		for _, term := range terms {
+			if strings.HasPrefix(lowerFragment[i:], term) && len(term) > len(matched) {
+				matched = term
+			}
+		}
+		if matched == "" {
+			_, size := utf8.DecodeRuneInString(fragment[i:])
+			b.WriteString(fragment[i : i+size])
+			i += size
+			continue
+		}
+		b.WriteString("<b>")
+		b.WriteString(fragment[i : i+len(matched)])
+		b.WriteString("</b>")
+		i += len(matched)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit]
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"AIChallengeNewsAPI/internal/lib/metrics"
+	"AIChallengeNewsAPI/internal/lib/migrate"
+	"context"
+	"database/sql"
+	"embed"
+	"log/slog"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey — ключ advisory lock: миграции, запущенные одновременно
+// несколькими репликами, выполняются по очереди.
+const migrationLockKey = 7_243_512_911
+
+var dialect = migrate.Dialect{
+	Lock: func(ctx context.Context, conn *sql.Conn) (func(), error) {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+			return nil, err
+		}
+		return func() {
+			conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+		}, nil
+	},
+	TableExistsQuery: "SELECT to_regclass('schema_migrations') IS NOT NULL",
+	TimestampType:    "TIMESTAMPTZ",
+}
+
+func (repo *Repository) migrator() (*migrate.Migrator, error) {
+	return migrate.New(repo.db, dialect, migrationFiles, "migrations")
+}
+
+// Migrate применяет все ещё не применённые миграции.
+func (repo *Repository) Migrate(ctx context.Context, log *slog.Logger) error {
+	m, err := repo.migrator()
+	if err != nil {
+		return err
+	}
+	return m.Up(ctx, log)
+}
+
+// MigrateDown откатывает steps последних применённых миграций.
+func (repo *Repository) MigrateDown(ctx context.Context, log *slog.Logger, steps int) error {
+	m, err := repo.migrator()
+	if err != nil {
+		return err
+	}
+	return m.Down(ctx, log, steps)
+}
+
+// MigrationStatus возвращает все известные миграции с отметкой о применении.
+func (repo *Repository) MigrationStatus(ctx context.Context) ([]migrate.Status, error) {
+	m, err := repo.migrator()
+	if err != nil {
+		return nil, err
+	}
+	return m.Status(ctx)
+}
+
+// SchemaReady сообщает, применены ли все миграции, встроенные в эту сборку.
+func (repo *Repository) SchemaReady(ctx context.Context) (bool, error) {
+	defer metrics.ObserveQuery("schema_ready", time.Now())
+
+	m, err := repo.migrator()
+	if err != nil {
+		return false, err
+	}
+	return m.Ready(ctx)
+}
@@ -6,5 +6,4 @@ CREATE TABLE IF NOT EXISTS news (
     text TEXT NOT NULL,
     published_at TIMESTAMP);
 
-
-CREATE INDEX idx_news_url ON news (url);
+CREATE INDEX IF NOT EXISTS idx_news_url ON news (url);
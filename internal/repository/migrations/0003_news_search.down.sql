@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_news_search_vector;
+DROP INDEX IF EXISTS idx_news_published_at;
+ALTER TABLE news DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,7 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(text, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_news_published_at ON news (published_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_news_search_vector ON news USING GIN (search_vector);
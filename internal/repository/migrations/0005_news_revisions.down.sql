@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS news_revisions;
+DROP INDEX IF EXISTS idx_news_source_published_at;
+ALTER TABLE news DROP COLUMN IF EXISTS checked_at;
+ALTER TABLE news DROP COLUMN IF EXISTS content_hash;
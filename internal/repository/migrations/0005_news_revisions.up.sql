@@ -0,0 +1,14 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS content_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE news ADD COLUMN IF NOT EXISTS checked_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_news_source_published_at ON news (source, published_at DESC);
+
+CREATE TABLE IF NOT EXISTS news_revisions (
+    id SERIAL PRIMARY KEY,
+    news_id INTEGER NOT NULL REFERENCES news (id) ON DELETE CASCADE,
+    previous_hash TEXT NOT NULL,
+    content_hash TEXT NOT NULL,
+    diff TEXT NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_news_revisions_news_id ON news_revisions (news_id, detected_at DESC);
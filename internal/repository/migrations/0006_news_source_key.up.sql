@@ -0,0 +1,4 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS source_key TEXT NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS idx_news_source_published_at;
+CREATE INDEX IF NOT EXISTS idx_news_source_key_published_at ON news (source_key, published_at DESC);
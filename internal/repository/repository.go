@@ -2,26 +2,30 @@ package repository
 
 import (
 	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/lib/metrics"
+	"AIChallengeNewsAPI/internal/repository/sqlnews"
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type Repository struct {
 	db *sql.DB
 }
 
-func NewRepository(connectionString string, log *slog.Logger) (*Repository, error) {
+func NewRepository(ctx context.Context, connectionString string, log *slog.Logger) (*Repository, error) {
 	db, err := sql.Open("postgres", connectionString)
 	if err != nil {
 		log.Warn("cannot open to database news", slog.String("error", err.Error()))
 		return nil, err
 	}
 
-	err = waitForDB(db, log)
+	err = waitForDB(ctx, db, log)
 	if err != nil {
 		log.Warn("cannot connect to database news", slog.String("error", err.Error()))
 		return nil, err
@@ -30,70 +34,128 @@ func NewRepository(connectionString string, log *slog.Logger) (*Repository, erro
 	return &Repository{db: db}, nil
 }
 
-func (repo *Repository) AddNews(news entity.News) error {
-	query := "INSERT INTO news (title, url, source, published_at, text) VALUES ($1, $2, $3, $4, $5)"
-	_, err := repo.db.Exec(query, news.Title, news.Link, news.Source, news.PublishedAt, news.Text)
-	return err
+// Close закрывает пул соединений; вызывается при остановке приложения,
+// когда запросов к базе больше не будет.
+func (repo *Repository) Close() error {
+	return repo.db.Close()
 }
 
-func (repo *Repository) GetNewsById(id int) (*entity.News, error) {
-	var news entity.News
-	query := "SELECT title, url, source, published_at, text FROM news WHERE id = $1"
-	row := repo.db.QueryRow(query, id)
-	err := row.Scan(&news.Title, &news.Link, &news.Source, &news.PublishedAt, &news.Text)
-	if err != nil {
-		return nil, err
-	}
-	return &news, nil
+func (repo *Repository) Ping(ctx context.Context) error {
+	defer metrics.ObserveQuery("ping", time.Now())
+
+	return repo.db.PingContext(ctx)
 }
 
-func (repo *Repository) GetNewsByUrl(url string) (*entity.News, error) {
-	var news entity.News
-	query := "SELECT title, url, source, published_at, text FROM news WHERE url = $1"
-	row := repo.db.QueryRow(query, url)
-	err := row.Scan(&news.Title, &news.Link, &news.Source, &news.PublishedAt, &news.Text)
+// UpsertNewsBatch сохраняет новости одной транзакцией, пропуская уже
+// известные url (см. sqlnews.UpsertBatch).
+func (repo *Repository) UpsertNewsBatch(ctx context.Context, news []entity.News) ([]entity.News, error) {
+	defer metrics.ObserveQuery("upsert_news_batch", time.Now())
+
+	return sqlnews.UpsertBatch(ctx, repo.db, news)
+}
+
+func (repo *Repository) GetNewsById(ctx context.Context, id int) (*entity.News, error) {
+	defer metrics.ObserveQuery("get_news_by_id", time.Now())
+
+	return sqlnews.Get(ctx, repo.db, "SELECT "+sqlnews.Columns+" FROM news WHERE id = $1", id)
+}
+
+// ExistingUrls возвращает те из urls, что уже есть в таблице news, одним запросом.
+func (repo *Repository) ExistingUrls(ctx context.Context, urls []string) (map[string]struct{}, error) {
+	defer metrics.ObserveQuery("existing_urls", time.Now())
+
+	existing := make(map[string]struct{})
+	if len(urls) == 0 {
+		return existing, nil
+	}
+
+	rows, err := repo.db.QueryContext(ctx, "SELECT url FROM news WHERE url = ANY($1)", pq.Array(urls))
 	if err != nil {
 		return nil, err
 	}
-	return &news, nil
+	defer rows.Close()
+
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		existing[url] = struct{}{}
+	}
+
+	return existing, rows.Err()
 }
 
-func (repo *Repository) ContainNews(url string) (bool, error) {
-	var exists bool
-	query := "SELECT EXISTS (SELECT 1 FROM news WHERE url = $1)"
-	err := repo.db.QueryRow(query, url).Scan(&exists)
-	return exists, err
+func (repo *Repository) GetLatestNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error) {
+	defer metrics.ObserveQuery("get_latest_news", time.Now())
+
+	return sqlnews.Latest(ctx, repo.db, queryDialect, filter)
 }
 
-func (repo *Repository) GetLatestNews(k int) ([]entity.News, error) {
-	query := `SELECT title, url, source, published_at, text FROM news
-		ORDER BY published_at DESC LIMIT $1`
-	rows, err := repo.db.Query(query, k)
+// GetNewsAfterID возвращает до limit новостей с ID больше id в порядке ID —
+// так поток SSE дочитывает пропущенное после переподключения.
+func (repo *Repository) GetNewsAfterID(ctx context.Context, id int, limit int) ([]entity.News, error) {
+	defer metrics.ObserveQuery("get_news_after_id", time.Now())
+
+	return sqlnews.List(ctx, repo.db, "SELECT "+sqlnews.Columns+" FROM news WHERE id > $1 ORDER BY id ASC LIMIT $2", id, limit)
+}
+
+// SearchNews ищет по tsvector-колонке search_vector (словарь russian):
+// заголовок весит больше текста, выдача упорядочена по ts_rank.
+func (repo *Repository) SearchNews(ctx context.Context, text string, filter entity.NewsFilter) ([]entity.NewsSearchResult, error) {
+	defer metrics.ObserveQuery("search_news", time.Now())
+
+	conditions, args := sqlnews.FilterConditions(queryDialect, filter, []any{text}, "")
+	conditions = append([]string{"search_vector @@ q"}, conditions...)
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`SELECT %s,
+			ts_rank(search_vector, q) AS rank,
+			ts_headline('russian', text, q, 'StartSel=<b>, StopSel=</b>, MaxFragments=2, MaxWords=30, MinWords=10') AS headline
+		FROM news, websearch_to_tsquery('russian', $1) AS q
+		WHERE %s
+		ORDER BY rank DESC, published_at DESC, id DESC LIMIT $%d`, sqlnews.Columns, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var newsList []entity.News
+	var results []entity.NewsSearchResult
 	for rows.Next() {
-		var news entity.News
-		if err := rows.Scan(&news.Title, &news.Link, &news.Source, &news.PublishedAt, &news.Text); err != nil {
+		var result entity.NewsSearchResult
+		if err := sqlnews.Scan(rows, &result.News, &result.Rank, &result.Headline); err != nil {
 			return nil, err
 		}
-		newsList = append(newsList, news)
+		results = append(results, result)
 	}
 
-	return newsList, nil
+	return results, rows.Err()
 }
 
-func waitForDB(db *sql.DB, log *slog.Logger) error {
+// queryDialect сравнивает списки через = ANY с массивом Postgres.
+var queryDialect = sqlnews.Dialect{
+	InList: func(column string, arg int) string {
+		return fmt.Sprintf("lower(%s) = ANY($%d)", column, arg)
+	},
+	ListArg: func(values []string) any {
+		return pq.Array(values)
+	},
+}
+
+func waitForDB(ctx context.Context, db *sql.DB, log *slog.Logger) error {
 	for i := 0; i < 10; i++ {
-		err := db.Ping()
+		err := db.PingContext(ctx)
 		if err == nil {
 			return nil
 		}
 		log.Info("Waiting for database connection")
-		time.Sleep(2 * time.Second)
+		select {
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 	return fmt.Errorf("failed to connect to the database")
 }
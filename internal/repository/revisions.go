@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/lib/metrics"
+	"AIChallengeNewsAPI/internal/repository/sqlnews"
+	"context"
+	"time"
+)
+
+// GetNewsForRecheck возвращает статьи источника, которые пора проверить на
+// изменения: сначала те, что дольше всех не проверялись.
+func (repo *Repository) GetNewsForRecheck(ctx context.Context, filter entity.NewsRecheckFilter) ([]entity.News, error) {
+	defer metrics.ObserveQuery("get_news_for_recheck", time.Now())
+
+	return sqlnews.List(ctx, repo.db, `SELECT `+sqlnews.Columns+`
+		FROM news
+		WHERE source_key = $1 AND published_at >= $2 AND (checked_at IS NULL OR checked_at < $3)
+		ORDER BY checked_at ASC NULLS FIRST, id ASC LIMIT $4`,
+		filter.SourceKey, filter.PublishedAfter.UTC(), filter.CheckedBefore, filter.Limit)
+}
+
+// MarkNewsChecked отмечает, что статья перепроверена и не изменилась.
+// Заодно сохраняет хеш, если статья была сохранена до его появления.
+func (repo *Repository) MarkNewsChecked(ctx context.Context, id int, contentHash string, checkedAt time.Time) error {
+	defer metrics.ObserveQuery("mark_news_checked", time.Now())
+
+	_, err := repo.db.ExecContext(ctx, "UPDATE news SET content_hash = $1, checked_at = $2 WHERE id = $3",
+		contentHash, checkedAt, id)
+	return err
+}
+
+// AddNewsRevision в одной транзакции обновляет статью до новой версии
+// и сохраняет изменение в news_revisions.
+func (repo *Repository) AddNewsRevision(ctx context.Context, news entity.News, revision entity.NewsRevision) (*entity.NewsRevision, error) {
+	defer metrics.ObserveQuery("add_news_revision", time.Now())
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "UPDATE news SET title = $1, text = $2, content_hash = $3, checked_at = $4 WHERE id = $5",
+		news.Title, news.Text, revision.ContentHash, revision.DetectedAt, revision.NewsID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO news_revisions (news_id, previous_hash, content_hash, diff, detected_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		revision.NewsID, revision.PreviousHash, revision.ContentHash, revision.Diff, revision.DetectedAt).Scan(&revision.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetNewsRevisions возвращает изменения статьи, новые первыми.
+func (repo *Repository) GetNewsRevisions(ctx context.Context, newsID int) ([]entity.NewsRevision, error) {
+	defer metrics.ObserveQuery("get_news_revisions", time.Now())
+
+	rows, err := repo.db.QueryContext(ctx, `SELECT id, news_id, previous_hash, content_hash, diff, detected_at
+		FROM news_revisions
+		WHERE news_id = $1
+		ORDER BY detected_at DESC, id DESC`, newsID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []entity.NewsRevision
+	for rows.Next() {
+		var revision entity.NewsRevision
+		if err := rows.Scan(&revision.ID, &revision.NewsID, &revision.PreviousHash, &revision.ContentHash,
+			&revision.Diff, &revision.DetectedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
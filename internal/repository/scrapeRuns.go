@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/lib/metrics"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+// StartScrapeRun регистрирует запуск источника. Уникальный индекс по
+// незавершённым запускам не даёт двум запускам одного источника идти
+// одновременно; запуски, зависшие дольше staleAfter (например, после падения
+// процесса), предварительно помечаются как abandoned.
+func (repo *Repository) StartScrapeRun(ctx context.Context, source string, staleAfter time.Duration) (*entity.ScrapeRun, error) {
+	defer metrics.ObserveQuery("start_scrape_run", time.Now())
+
+	now := time.Now()
+
+	_, err := repo.db.ExecContext(ctx, `UPDATE scrape_runs SET status = $1, finished_at = $2, error = 'run did not finish'
+		WHERE source = $3 AND finished_at IS NULL AND started_at < $4`,
+		entity.RunStatusAbandoned, now, source, now.Add(-staleAfter))
+	if err != nil {
+		return nil, err
+	}
+
+	run := &entity.ScrapeRun{Source: source, Status: entity.RunStatusRunning, StartedAt: now}
+	err = repo.db.QueryRowContext(ctx, `INSERT INTO scrape_runs (source, status, started_at) VALUES ($1, $2, $3) RETURNING id`,
+		run.Source, run.Status, run.StartedAt).Scan(&run.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, entity.ErrRunInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (repo *Repository) FinishScrapeRun(ctx context.Context, run entity.ScrapeRun) error {
+	defer metrics.ObserveQuery("finish_scrape_run", time.Now())
+
+	_, err := repo.db.ExecContext(ctx, `UPDATE scrape_runs SET status = $1, finished_at = $2, digests = $3, fetched = $4,
+			inserted = $5, failed = $6, error = $7
+		WHERE id = $8`,
+		run.Status, run.FinishedAt, run.Digests, run.Fetched, run.Inserted, run.Failed, run.Error, run.ID)
+	return err
+}
+
+func (repo *Repository) GetScrapeRuns(ctx context.Context, filter entity.ScrapeRunFilter) ([]entity.ScrapeRun, error) {
+	defer metrics.ObserveQuery("get_scrape_runs", time.Now())
+
+	rows, err := repo.db.QueryContext(ctx, `SELECT id, source, status, started_at, finished_at, digests, fetched, inserted, failed, error
+		FROM scrape_runs
+		WHERE $1 = '' OR source = $1
+		ORDER BY started_at DESC, id DESC LIMIT $2`, filter.Source, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []entity.ScrapeRun
+	for rows.Next() {
+		var run entity.ScrapeRun
+		if err := rows.Scan(&run.ID, &run.Source, &run.Status, &run.StartedAt, &run.FinishedAt,
+			&run.Digests, &run.Fetched, &run.Inserted, &run.Failed, &run.Error); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"AIChallengeNewsAPI/internal/lib/metrics"
+	"AIChallengeNewsAPI/internal/lib/migrate"
+	"context"
+	"database/sql"
+	"embed"
+	"log/slog"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+const (
+	// migrationLockStaleAfter — через сколько блокировку, оставленную упавшим
+	// процессом, можно забрать.
+	migrationLockStaleAfter = 10 * time.Minute
+	migrationLockRetry      = 100 * time.Millisecond
+)
+
+// dialect: advisory lock в SQLite нет, поэтому блокировкой служит
+// единственная строка таблицы schema_migrations_lock. Её вставка атомарна,
+// так что миграции, запущенные одновременно несколькими процессами над
+// одним файлом, выполняются по очереди.
+var dialect = migrate.Dialect{
+	Lock:             lockMigrations,
+	TableExistsQuery: "SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')",
+	TimestampType:    "DATETIME",
+}
+
+func lockMigrations(ctx context.Context, conn *sql.Conn) (func(), error) {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		locked_at DATETIME NOT NULL)`)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		now := time.Now().UTC()
+		if _, err := conn.ExecContext(ctx, "DELETE FROM schema_migrations_lock WHERE locked_at < $1",
+			now.Add(-migrationLockStaleAfter)); err != nil {
+			return nil, err
+		}
+		result, err := conn.ExecContext(ctx, "INSERT INTO schema_migrations_lock (id, locked_at) VALUES (1, $1) ON CONFLICT DO NOTHING", now)
+		if err != nil {
+			return nil, err
+		}
+		if locked, _ := result.RowsAffected(); locked == 1 {
+			return func() {
+				conn.ExecContext(context.WithoutCancel(ctx), "DELETE FROM schema_migrations_lock WHERE id = 1")
+			}, nil
+		}
+
+		select {
+		case <-time.After(migrationLockRetry):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (repo *Repository) migrator() (*migrate.Migrator, error) {
+	return migrate.New(repo.db, dialect, migrationFiles, "migrations")
+}
+
+// Migrate применяет все ещё не применённые миграции.
+func (repo *Repository) Migrate(ctx context.Context, log *slog.Logger) error {
+	m, err := repo.migrator()
+	if err != nil {
+		return err
+	}
+	return m.Up(ctx, log)
+}
+
+// MigrateDown откатывает steps последних применённых миграций.
+func (repo *Repository) MigrateDown(ctx context.Context, log *slog.Logger, steps int) error {
+	m, err := repo.migrator()
+	if err != nil {
+		return err
+	}
+	return m.Down(ctx, log, steps)
+}
+
+// MigrationStatus возвращает все известные миграции с отметкой о применении.
+func (repo *Repository) MigrationStatus(ctx context.Context) ([]migrate.Status, error) {
+	m, err := repo.migrator()
+	if err != nil {
+		return nil, err
+	}
+	return m.Status(ctx)
+}
+
+// SchemaReady сообщает, применены ли все миграции, встроенные в эту сборку.
+func (repo *Repository) SchemaReady(ctx context.Context) (bool, error) {
+	defer metrics.ObserveQuery("schema_ready", time.Now())
+
+	m, err := repo.migrator()
+	if err != nil {
+		return false, err
+	}
+	return m.Ready(ctx)
+}
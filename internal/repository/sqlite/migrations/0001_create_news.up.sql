@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS news (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL UNIQUE,
+    source TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    text TEXT NOT NULL,
+    published_at DATETIME);
+
+CREATE INDEX IF NOT EXISTS idx_news_published_at ON news (published_at DESC, id DESC);
@@ -0,0 +1,23 @@
+-- Внешнее содержимое: FTS-индекс хранит только токены, сами тексты берутся
+-- из news, а триггеры держат индекс в актуальном состоянии.
+CREATE VIRTUAL TABLE IF NOT EXISTS news_fts USING fts5 (
+    title,
+    text,
+    content = 'news',
+    content_rowid = 'id',
+    tokenize = 'unicode61');
+
+CREATE TRIGGER IF NOT EXISTS news_fts_insert AFTER INSERT ON news BEGIN
+    INSERT INTO news_fts (rowid, title, text) VALUES (new.id, new.title, new.text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS news_fts_delete AFTER DELETE ON news BEGIN
+    INSERT INTO news_fts (news_fts, rowid, title, text) VALUES ('delete', old.id, old.title, old.text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS news_fts_update AFTER UPDATE OF title, text ON news BEGIN
+    INSERT INTO news_fts (news_fts, rowid, title, text) VALUES ('delete', old.id, old.title, old.text);
+    INSERT INTO news_fts (rowid, title, text) VALUES (new.id, new.title, new.text);
+END;
+
+INSERT INTO news_fts (news_fts) VALUES ('rebuild');
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS scrape_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source TEXT NOT NULL,
+    status TEXT NOT NULL,
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME,
+    digests INTEGER NOT NULL DEFAULT 0,
+    fetched INTEGER NOT NULL DEFAULT 0,
+    inserted INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '');
+
+CREATE INDEX IF NOT EXISTS idx_scrape_runs_source_started_at ON scrape_runs (source, started_at DESC);
+-- Не более одного незавершённого запуска на источник, в том числе между процессами.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scrape_runs_one_running ON scrape_runs (source) WHERE finished_at IS NULL;
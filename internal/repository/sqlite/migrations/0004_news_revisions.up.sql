@@ -0,0 +1,14 @@
+ALTER TABLE news ADD COLUMN content_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE news ADD COLUMN checked_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_news_source_published_at ON news (source, published_at DESC);
+
+CREATE TABLE IF NOT EXISTS news_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    news_id INTEGER NOT NULL REFERENCES news (id) ON DELETE CASCADE,
+    previous_hash TEXT NOT NULL,
+    content_hash TEXT NOT NULL,
+    diff TEXT NOT NULL,
+    detected_at DATETIME NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_news_revisions_news_id ON news_revisions (news_id, detected_at DESC);
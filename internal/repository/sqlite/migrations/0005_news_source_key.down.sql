@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_news_source_key_published_at;
+CREATE INDEX IF NOT EXISTS idx_news_source_published_at ON news (source, published_at DESC);
+ALTER TABLE news DROP COLUMN source_key;
@@ -0,0 +1,199 @@
+// Package sqlite — реализация interfaces.RepositoryInter поверх встроенной
+// SQLite (чистый Go, без cgo): один файл базы, без отдельного сервера.
+// Схема, миграции и поведение методов повторяют версию для Postgres;
+// общие с ней запросы — в пакете sqlnews.
+package sqlite
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/lib/metrics"
+	"AIChallengeNewsAPI/internal/repository/sqlnews"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sqlitedrv "modernc.org/sqlite"
+)
+
+func init() {
+	// Встроенная lower() в SQLite меняет регистр только у ASCII, а источники
+	// и категории бывают кириллическими.
+	sqlitedrv.MustRegisterDeterministicScalarFunction("fold", 1,
+		func(ctx *sqlitedrv.FunctionContext, args []driver.Value) (driver.Value, error) {
+			s, ok := args[0].(string)
+			if !ok {
+				return args[0], nil
+			}
+			return strings.ToLower(s), nil
+		})
+}
+
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository открывает (и при необходимости создаёт) файл базы path.
+// WAL позволяет читать во время записи, busy_timeout — дождаться блокировки
+// другого соединения вместо ошибки SQLITE_BUSY, а BEGIN IMMEDIATE берёт
+// блокировку записи в начале транзакции, а не при первом изменении.
+func NewRepository(ctx context.Context, path string, log *slog.Logger) (*Repository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Warn("cannot create database directory", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "sqlite")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		log.Warn("cannot open database news", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		log.Warn("cannot connect to database news", slog.String("error", err.Error()))
+		db.Close()
+		return nil, err
+	}
+
+	return &Repository{db: db}, nil
+}
+
+func (repo *Repository) Close() error {
+	return repo.db.Close()
+}
+
+func (repo *Repository) Ping(ctx context.Context) error {
+	defer metrics.ObserveQuery("ping", time.Now())
+
+	return repo.db.PingContext(ctx)
+}
+
+// UpsertNewsBatch сохраняет новости одной транзакцией, пропуская уже
+// известные url (см. sqlnews.UpsertBatch).
+func (repo *Repository) UpsertNewsBatch(ctx context.Context, news []entity.News) ([]entity.News, error) {
+	defer metrics.ObserveQuery("upsert_news_batch", time.Now())
+
+	return sqlnews.UpsertBatch(ctx, repo.db, news)
+}
+
+func (repo *Repository) GetNewsById(ctx context.Context, id int) (*entity.News, error) {
+	defer metrics.ObserveQuery("get_news_by_id", time.Now())
+
+	return sqlnews.Get(ctx, repo.db, "SELECT "+sqlnews.Columns+" FROM news WHERE id = $1", id)
+}
+
+// ExistingUrls возвращает те из urls, что уже есть в таблице news, одним
+// запросом: список передаётся JSON-массивом и разворачивается json_each.
+func (repo *Repository) ExistingUrls(ctx context.Context, urls []string) (map[string]struct{}, error) {
+	defer metrics.ObserveQuery("existing_urls", time.Now())
+
+	existing := make(map[string]struct{})
+	if len(urls) == 0 {
+		return existing, nil
+	}
+
+	rows, err := repo.db.QueryContext(ctx, "SELECT url FROM news WHERE url IN (SELECT value FROM json_each($1))", jsonArray(urls))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		existing[url] = struct{}{}
+	}
+
+	return existing, rows.Err()
+}
+
+func (repo *Repository) GetLatestNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error) {
+	defer metrics.ObserveQuery("get_latest_news", time.Now())
+
+	return sqlnews.Latest(ctx, repo.db, queryDialect, filter)
+}
+
+// GetNewsAfterID возвращает до limit новостей с ID больше id в порядке ID —
+// так поток SSE дочитывает пропущенное после переподключения.
+func (repo *Repository) GetNewsAfterID(ctx context.Context, id int, limit int) ([]entity.News, error) {
+	defer metrics.ObserveQuery("get_news_after_id", time.Now())
+
+	return sqlnews.List(ctx, repo.db, "SELECT "+sqlnews.Columns+" FROM news WHERE id > $1 ORDER BY id ASC LIMIT $2", id, limit)
+}
+
+// SearchNews ищет по FTS5-индексу news_fts. Запрос в духе websearch_to_tsquery
+// переводится в синтаксис FTS5 (см. ftsQuery); заголовок весит больше текста,
+// выдача упорядочена по bm25. Морфологии, как у словаря russian в Postgres,
+// здесь нет — слова ищутся по префиксу.
+func (repo *Repository) SearchNews(ctx context.Context, text string, filter entity.NewsFilter) ([]entity.NewsSearchResult, error) {
+	defer metrics.ObserveQuery("search_news", time.Now())
+
+	match := ftsQuery(text)
+	if match == "" {
+		return nil, nil
+	}
+
+	// Столбцы есть и в news, и в news_fts, поэтому условия квалифицированы.
+	conditions, args := sqlnews.FilterConditions(queryDialect, filter, []any{match}, "news.")
+	conditions = append([]string{"news_fts MATCH $1"}, conditions...)
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`SELECT %s,
+			-bm25(news_fts, %g, %g) AS rank,
+			snippet(news_fts, 1, '<b>', '</b>', '…', 30) AS headline
+		FROM news_fts JOIN news ON news.id = news_fts.rowid
+		WHERE %s
+		ORDER BY rank DESC, news.published_at DESC, news.id DESC LIMIT $%d`, newsColumns, titleWeight, textWeight, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []entity.NewsSearchResult
+	for rows.Next() {
+		var result entity.NewsSearchResult
+		if err := sqlnews.Scan(rows, &result.News, &result.Rank, &result.Headline); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// queryDialect передаёт списки JSON-массивом и сравнивает через fold, потому что
+// lower() в SQLite не знает кириллицы.
+var queryDialect = sqlnews.Dialect{
+	InList: func(column string, arg int) string {
+		return fmt.Sprintf("fold(%s) IN (SELECT value FROM json_each($%d))", column, arg)
+	},
+	ListArg: func(values []string) any {
+		return jsonArray(values)
+	},
+}
+
+// newsColumns — sqlnews.Columns с префиксом news. для запросов с JOIN.
+var newsColumns = "news." + strings.ReplaceAll(sqlnews.Columns, ", ", ", news.")
+
+func jsonArray(values []string) string {
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
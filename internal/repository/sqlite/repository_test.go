@@ -0,0 +1,356 @@
+package sqlite
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo, err := NewRepository(ctx, filepath.Join(t.TempDir(), "news.db"), log)
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	if err := repo.Migrate(ctx, log); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	return repo
+}
+
+func upsert(t *testing.T, repo *Repository, news ...entity.News) []entity.News {
+	t.Helper()
+
+	inserted, err := repo.UpsertNewsBatch(context.Background(), news)
+	if err != nil {
+		t.Fatalf("UpsertNewsBatch: %v", err)
+	}
+	return inserted
+}
+
+func links(news []entity.News) []string {
+	result := make([]string, len(news))
+	for i, item := range news {
+		result[i] = item.Link
+	}
+	return result
+}
+
+func TestUpsertNewsBatchSkipsKnownUrls(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	publishedAt := time.Date(2024, 10, 25, 13, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	first := upsert(t, repo, entity.News{
+		Title:       "Банк России сохранил ставку",
+		Link:        "https://example.com/news/1",
+		Source:      "Интерфакс",
+		SourceKey:   "interfax",
+		Category:    "Экономика",
+		PublishedAt: publishedAt,
+		Text:        "Совет директоров сохранил ставку.",
+		ContentHash: "hash-1",
+	})
+	if len(first) != 1 || first[0].ID == 0 {
+		t.Fatalf("first upsert returned %+v, want one news with an ID", first)
+	}
+
+	// Первая новость уже есть, вторая встречается в пачке дважды.
+	second := upsert(t, repo,
+		entity.News{Title: "Дубль", Link: "https://example.com/news/1", PublishedAt: publishedAt},
+		entity.News{Title: "Нефть подорожала", Link: "https://example.com/news/2", PublishedAt: publishedAt},
+		entity.News{Title: "Нефть подорожала", Link: "https://example.com/news/2", PublishedAt: publishedAt},
+	)
+	if got := links(second); len(got) != 1 || got[0] != "https://example.com/news/2" {
+		t.Fatalf("second upsert inserted %v, want only news/2", got)
+	}
+
+	stored, err := repo.GetNewsById(ctx, first[0].ID)
+	if err != nil {
+		t.Fatalf("GetNewsById: %v", err)
+	}
+	if stored.Title != "Банк России сохранил ставку" || stored.SourceKey != "interfax" ||
+		stored.Category != "Экономика" || stored.ContentHash != "hash-1" {
+		t.Errorf("stored news = %+v", *stored)
+	}
+	if !stored.PublishedAt.Equal(publishedAt) {
+		t.Errorf("PublishedAt = %v, want %v", stored.PublishedAt, publishedAt)
+	}
+
+	if _, err := repo.GetNewsById(ctx, 1000); !errors.Is(err, entity.ErrNewsNotFound) {
+		t.Errorf("GetNewsById for a missing id: got %v, want ErrNewsNotFound", err)
+	}
+
+	existing, err := repo.ExistingUrls(ctx, []string{"https://example.com/news/1", "https://example.com/news/3"})
+	if err != nil {
+		t.Fatalf("ExistingUrls: %v", err)
+	}
+	if _, ok := existing["https://example.com/news/1"]; !ok || len(existing) != 1 {
+		t.Errorf("ExistingUrls = %v, want only news/1", existing)
+	}
+}
+
+func TestGetLatestNewsPaginatesWithCursor(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	base := time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC)
+
+	// Пары новостей с одинаковым временем: курсор обязан различать их по ID.
+	var news []entity.News
+	for i := 1; i <= 7; i++ {
+		news = append(news, entity.News{
+			Title:       fmt.Sprintf("Новость %d", i),
+			Link:        fmt.Sprintf("https://example.com/news/%d", i),
+			Source:      "example.com",
+			PublishedAt: base.Add(time.Duration(i/2) * time.Hour),
+		})
+	}
+	upsert(t, repo, news...)
+
+	var got []string
+	filter := entity.NewsFilter{Limit: 3}
+	for page := 0; page < 5; page++ {
+		items, err := repo.GetLatestNews(ctx, filter)
+		if err != nil {
+			t.Fatalf("GetLatestNews: %v", err)
+		}
+		got = append(got, links(items)...)
+		if len(items) < filter.Limit {
+			break
+		}
+		last := items[len(items)-1]
+		filter.After = &entity.NewsCursor{PublishedAt: last.PublishedAt, ID: last.ID}
+	}
+
+	want := []string{"7", "6", "5", "4", "3", "2", "1"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want news %v", got, want)
+	}
+	for i := range want {
+		if got[i] != "https://example.com/news/"+want[i] {
+			t.Fatalf("got %v, want news %v", got, want)
+		}
+	}
+}
+
+func TestGetLatestNewsFilters(t *testing.T) {
+	repo := newTestRepository(t)
+	base := time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC)
+
+	upsert(t, repo,
+		entity.News{Title: "1", Link: "https://example.com/news/1", Source: "Интерфакс", Category: "Экономика", PublishedAt: base},
+		entity.News{Title: "2", Link: "https://example.com/news/2", Source: "РБК", Category: "Рынки", PublishedAt: base.Add(time.Hour)},
+		entity.News{Title: "3", Link: "https://example.com/news/3", Source: "Интерфакс", Category: "Рынки", PublishedAt: base.Add(2 * time.Hour)},
+	)
+
+	msk := time.FixedZone("MSK", 3*60*60)
+	tests := []struct {
+		name   string
+		filter entity.NewsFilter
+		want   []string
+	}{
+		{
+			name:   "source, cyrillic case-insensitive",
+			filter: entity.NewsFilter{Sources: []string{"интерфакс"}},
+			want:   []string{"https://example.com/news/3", "https://example.com/news/1"},
+		},
+		{
+			name:   "category",
+			filter: entity.NewsFilter{Categories: []string{"РЫНКИ"}},
+			want:   []string{"https://example.com/news/3", "https://example.com/news/2"},
+		},
+		{
+			name:   "interval in another time zone",
+			filter: entity.NewsFilter{From: base.Add(time.Hour).In(msk), To: base.Add(time.Hour).In(msk)},
+			want:   []string{"https://example.com/news/2"},
+		},
+		{
+			name:   "source and category",
+			filter: entity.NewsFilter{Sources: []string{"Интерфакс"}, Categories: []string{"Экономика"}},
+			want:   []string{"https://example.com/news/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.Limit = 10
+			items, err := repo.GetLatestNews(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("GetLatestNews: %v", err)
+			}
+			if got := links(items); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchNews(t *testing.T) {
+	repo := newTestRepository(t)
+	base := time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC)
+
+	upsert(t, repo,
+		entity.News{Title: "Ставка осталась прежней", Link: "https://example.com/news/1", Source: "Интерфакс",
+			Text: "Банк России сохранил ключевую ставку на уровне 21%.", PublishedAt: base},
+		entity.News{Title: "Банк России сохранил ставку", Link: "https://example.com/news/2", Source: "РБК",
+			Text: "Решение совпало с ожиданиями аналитиков.", PublishedAt: base.Add(-time.Hour)},
+		entity.News{Title: "Нефть подорожала", Link: "https://example.com/news/3", Source: "РБК",
+			Text: "Brent превысила 75 долларов, банки пересмотрели прогнозы.", PublishedAt: base},
+	)
+
+	tests := []struct {
+		name   string
+		query  string
+		filter entity.NewsFilter
+		want   []string
+	}{
+		{
+			name:  "title outranks text",
+			query: "банк россии",
+			want:  []string{"https://example.com/news/2", "https://example.com/news/1"},
+		},
+		{
+			name:  "prefix",
+			query: "подорож",
+			want:  []string{"https://example.com/news/3"},
+		},
+		{
+			name:  "exclusion",
+			query: "ставк -аналитиков",
+			want:  []string{"https://example.com/news/1"},
+		},
+		{
+			name:  "phrase",
+			query: `"ключевую ставку"`,
+			want:  []string{"https://example.com/news/1"},
+		},
+		{
+			name:   "with source filter",
+			query:  "банк",
+			filter: entity.NewsFilter{Sources: []string{"рбк"}},
+			want:   []string{"https://example.com/news/2", "https://example.com/news/3"},
+		},
+		{
+			name:  "only punctuation",
+			query: `"" - ?`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.Limit = 10
+			results, err := repo.SearchNews(context.Background(), tt.query, tt.filter)
+			if err != nil {
+				t.Fatalf("SearchNews: %v", err)
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, result.Link)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewsRevisions(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	inserted := upsert(t, repo,
+		entity.News{Title: "Старая", Link: "https://example.com/news/1", SourceKey: "example", PublishedAt: now.Add(-72 * time.Hour)},
+		entity.News{Title: "Первая", Link: "https://example.com/news/2", SourceKey: "example", PublishedAt: now.Add(-2 * time.Hour)},
+		entity.News{Title: "Вторая", Link: "https://example.com/news/3", SourceKey: "example", PublishedAt: now.Add(-time.Hour)},
+		entity.News{Title: "Чужая", Link: "https://example.com/news/4", SourceKey: "other", PublishedAt: now.Add(-time.Hour)},
+	)
+	first, second := inserted[1], inserted[2]
+
+	// Только что сохранённые статьи проверены при вставке.
+	filter := entity.NewsRecheckFilter{SourceKey: "example", PublishedAfter: now.Add(-24 * time.Hour), CheckedBefore: now.Add(-time.Minute), Limit: 10}
+	if due, err := repo.GetNewsForRecheck(ctx, filter); err != nil || len(due) != 0 {
+		t.Fatalf("GetNewsForRecheck right after insert = %v, %v; want nothing", links(due), err)
+	}
+
+	filter.CheckedBefore = now.Add(time.Minute)
+	if err := repo.MarkNewsChecked(ctx, second.ID, "hash-2", now.Add(-30*time.Minute)); err != nil {
+		t.Fatalf("MarkNewsChecked: %v", err)
+	}
+	due, err := repo.GetNewsForRecheck(ctx, filter)
+	if err != nil {
+		t.Fatalf("GetNewsForRecheck: %v", err)
+	}
+	// Дольше всех не проверявшаяся — первой; старая и чужая не попадают.
+	if got := links(due); fmt.Sprint(got) != fmt.Sprint([]string{second.Link, first.Link}) {
+		t.Fatalf("GetNewsForRecheck = %v, want [%s %s]", got, second.Link, first.Link)
+	}
+
+	revision, err := repo.AddNewsRevision(ctx, entity.News{Title: "Первая, обновлённая", Text: "Новый текст"}, entity.NewsRevision{
+		NewsID:       first.ID,
+		PreviousHash: "",
+		ContentHash:  "hash-1b",
+		Diff:         "-Первая\n+Первая, обновлённая",
+		DetectedAt:   now,
+	})
+	if err != nil {
+		t.Fatalf("AddNewsRevision: %v", err)
+	}
+	if revision.ID == 0 {
+		t.Error("revision has no ID")
+	}
+
+	updated, err := repo.GetNewsById(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetNewsById: %v", err)
+	}
+	if updated.Title != "Первая, обновлённая" || updated.Text != "Новый текст" || updated.ContentHash != "hash-1b" {
+		t.Errorf("news after revision = %+v", *updated)
+	}
+
+	revisions, err := repo.GetNewsRevisions(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetNewsRevisions: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Diff != revision.Diff || !revisions[0].DetectedAt.Equal(now) {
+		t.Errorf("GetNewsRevisions = %+v, want %+v", revisions, *revision)
+	}
+}
+
+func TestMigrateIsIdempotentAndReversible(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	if err := repo.Migrate(ctx, log); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+	if ready, err := repo.SchemaReady(ctx); err != nil || !ready {
+		t.Fatalf("SchemaReady = %v, %v; want true", ready, err)
+	}
+
+	statuses, err := repo.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	if err := repo.MigrateDown(ctx, log, len(statuses)); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if ready, _ := repo.SchemaReady(ctx); ready {
+		t.Error("schema is ready after rolling back every migration")
+	}
+	if err := repo.Migrate(ctx, log); err != nil {
+		t.Fatalf("Migrate after MigrateDown: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import "strings"
+
+// Веса столбцов для bm25 — как setweight A/B у search_vector в Postgres:
+// совпадение в заголовке важнее совпадения в тексте.
+const (
+	titleWeight = 10.0
+	textWeight  = 4.0
+)
+
+// ftsQuery переводит запрос в духе websearch_to_tsquery в синтаксис FTS5:
+// "фраза в кавычках" ищется целиком, слово с минусом исключает новость,
+// остальные слова обязательны и ищутся по префиксу. Каждый терм берётся
+// в кавычки FTS5, поэтому операторы и спецсимволы из ввода пользователя
+// не ломают запрос. Пустая строка означает, что искать нечего.
+func ftsQuery(text string) string {
+	var include, exclude []string
+
+	for len(text) > 0 {
+		text = strings.TrimLeft(text, " \t\n")
+		if text == "" {
+			break
+		}
+
+		negate := false
+		if text[0] == '-' {
+			negate = true
+			text = text[1:]
+		}
+
+		var term string
+		phrase := strings.HasPrefix(text, `"`)
+		if phrase {
+			end := strings.Index(text[1:], `"`)
+			if end < 0 {
+				term, text = text[1:], ""
+			} else {
+				term, text = text[1:end+1], text[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(text, " \t\n")
+			if end < 0 {
+				term, text = text, ""
+			} else {
+				term, text = text[:end], text[end:]
+			}
+		}
+
+		term = strings.TrimSpace(term)
+		if !hasToken(term) {
+			continue
+		}
+		quoted := `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+		if !phrase && !negate {
+			quoted += "*"
+		}
+		if negate {
+			exclude = append(exclude, quoted)
+		} else {
+			include = append(include, quoted)
+		}
+	}
+
+	if len(include) == 0 {
+		return ""
+	}
+	query := strings.Join(include, " AND ")
+	for _, term := range exclude {
+		query += " NOT " + term
+	}
+	return query
+}
+
+// hasToken сообщает, останется ли от терма хоть один токен после
+// токенизатора unicode61, который отбрасывает пунктуацию.
+func hasToken(term string) bool {
+	return strings.IndexFunc(term, func(r rune) bool {
+		return r >= 0x80 || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+	}) >= 0
+}
@@ -0,0 +1,26 @@
+package sqlite
+
+import "testing"
+
+func TestFtsQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "банк россии", want: `"банк"* AND "россии"*`},
+		{text: `"ключевую ставку" ЦБ`, want: `"ключевую ставку" AND "ЦБ"*`},
+		{text: "нефть -brent", want: `"нефть"* NOT "brent"`},
+		{text: `ставка "незакрытая фраза`, want: `"ставка"* AND "незакрытая фраза"`},
+		{text: `OR NEAR(a b) x"y`, want: `"OR"* AND "NEAR(a"* AND "b)"* AND "x""y"*`},
+		{text: "-только исключение", want: `"исключение"* NOT "только"`},
+		{text: "-нефть", want: ""},
+		{text: `"" - ?`, want: ""},
+		{text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := ftsQuery(tt.text); got != tt.want {
+			t.Errorf("ftsQuery(%q) = %s, want %s", tt.text, got, tt.want)
+		}
+	}
+}
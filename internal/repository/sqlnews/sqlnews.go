@@ -0,0 +1,200 @@
+// Package sqlnews — общий для Postgres и SQLite SQL таблицы news. Обе базы
+// понимают плейсхолдеры $N, INSERT ... ON CONFLICT ... RETURNING и сравнение
+// кортежей, поэтому запросы совпадают; различия (как передать список
+// значений и сравнить без учёта регистра) описывает Dialect.
+package sqlnews
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Columns — столбцы news в порядке, который ожидает Scan.
+const Columns = "id, title, url, source, source_key, category, published_at, text, content_hash"
+
+// upsertBatchSize ограничивает число строк в одном INSERT: у Postgres не
+// больше 65535 параметров на запрос, у SQLite предел ещё меньше.
+const upsertBatchSize = 500
+
+// Dialect — то, чем СУБД отличаются в запросах к news.
+type Dialect struct {
+	// InList возвращает условие «значение column без учёта регистра входит
+	// в список», переданный параметром $arg.
+	InList func(column string, arg int) string
+	// ListArg превращает список строк в значение одного параметра.
+	ListArg func(values []string) any
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Scan читает строку со столбцами Columns, за которыми идут extra.
+func Scan(row scanner, news *entity.News, extra ...any) error {
+	dest := []any{&news.ID, &news.Title, &news.Link, &news.Source, &news.SourceKey, &news.Category,
+		&news.PublishedAt, &news.Text, &news.ContentHash}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// Get выполняет запрос одной новости; нет строки — entity.ErrNewsNotFound.
+func Get(ctx context.Context, db *sql.DB, query string, args ...any) (*entity.News, error) {
+	var news entity.News
+	err := Scan(db.QueryRowContext(ctx, query, args...), &news)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrNewsNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &news, nil
+}
+
+// List выполняет запрос, возвращающий столбцы Columns.
+func List(ctx context.Context, q querier, query string, args ...any) ([]entity.News, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var newsList []entity.News
+	for rows.Next() {
+		var news entity.News
+		if err := Scan(rows, &news); err != nil {
+			return nil, err
+		}
+		newsList = append(newsList, news)
+	}
+
+	return newsList, rows.Err()
+}
+
+// UpsertBatch сохраняет новости в одной транзакции многострочными INSERT.
+// Новости с уже известным url пропускаются (ON CONFLICT DO NOTHING), поэтому
+// одновременная запись из нескольких реплик не создаёт дубликатов. Возвращает
+// только вставленные новости — в исходном порядке и с заполненным ID.
+func UpsertBatch(ctx context.Context, db *sql.DB, news []entity.News) ([]entity.News, error) {
+	if len(news) == 0 {
+		return nil, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	checkedAt := time.Now().UTC()
+	ids := make(map[string]int, len(news))
+	for start := 0; start < len(news); start += upsertBatchSize {
+		batch := news[start:min(start+upsertBatchSize, len(news))]
+
+		values := make([]string, 0, len(batch))
+		args := make([]any, 0, len(batch)*9)
+		for _, item := range batch {
+			n := len(args)
+			values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9))
+			args = append(args, item.Title, item.Link, item.Source, item.SourceKey, item.Category, item.PublishedAt.UTC(), item.Text, item.ContentHash, checkedAt)
+		}
+
+		query := "INSERT INTO news (title, url, source, source_key, category, published_at, text, content_hash, checked_at) VALUES " +
+			strings.Join(values, ", ") + " ON CONFLICT (url) DO NOTHING RETURNING id, url"
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id int
+			var url string
+			if err := rows.Scan(&id, &url); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			ids[url] = id
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	inserted := make([]entity.News, 0, len(ids))
+	for _, item := range news {
+		if id, ok := ids[item.Link]; ok {
+			item.ID = id
+			inserted = append(inserted, item)
+			delete(ids, item.Link)
+		}
+	}
+	return inserted, nil
+}
+
+// Latest возвращает страницу новостей по фильтру в порядке
+// (published_at DESC, id DESC), начиная после курсора filter.After.
+func Latest(ctx context.Context, db *sql.DB, dialect Dialect, filter entity.NewsFilter) ([]entity.News, error) {
+	conditions, args := FilterConditions(dialect, filter, nil, "")
+
+	if filter.After != nil {
+		args = append(args, filter.After.PublishedAt.UTC(), filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(published_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := "SELECT " + Columns + " FROM news"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY published_at DESC, id DESC LIMIT $%d", len(args))
+
+	return List(ctx, db, query, args...)
+}
+
+// FilterConditions строит условия фильтра, нумеруя параметры после args.
+// prefix квалифицирует столбцы ("news."), если в запросе несколько таблиц.
+//
+// Границы интервала приводятся к UTC: published_at в Postgres — TIMESTAMP
+// без часового пояса, и при сравнении смещение просто отбросилось бы.
+// По той же причине published_at и записывается в UTC.
+func FilterConditions(dialect Dialect, filter entity.NewsFilter, args []any, prefix string) ([]string, []any) {
+	var conditions []string
+
+	if len(filter.Sources) > 0 {
+		args = append(args, dialect.ListArg(lowerAll(filter.Sources)))
+		conditions = append(conditions, dialect.InList(prefix+"source", len(args)))
+	}
+	if len(filter.Categories) > 0 {
+		args = append(args, dialect.ListArg(lowerAll(filter.Categories)))
+		conditions = append(conditions, dialect.InList(prefix+"category", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		conditions = append(conditions, fmt.Sprintf("%spublished_at >= $%d", prefix, len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("%spublished_at <= $%d", prefix, len(args)))
+	}
+
+	return conditions, args
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"sync"
+)
+
+const subscriberBufferSize = 64
+
+// newsBroker рассылает только что сохранённые новости подписчикам.
+// Истории событий он не хранит: переподключившийся клиент дочитывает
+// пропущенное из базы по Last-Event-ID.
+type newsBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan entity.News]struct{}
+	closed      bool
+}
+
+func newNewsBroker() *newsBroker {
+	return &newsBroker{
+		subscribers: make(map[chan entity.News]struct{}),
+	}
+}
+
+func (b *newsBroker) Publish(news entity.News) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- news:
+		default:
+			// Подписчик не успевает читать: закрываем канал, клиент
+			// переподключится и дочитает пропущенное из базы.
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe возвращает канал новых событий. Канал закрывается брокером,
+// если подписчик отстал; cancel нужно вызвать всегда.
+func (b *newsBroker) Subscribe() (<-chan entity.News, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan entity.News, subscriberBufferSize)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, cancel
+}
+
+// Close закрывает каналы всех подписчиков, чтобы открытые потоки завершились
+// при остановке сервера; новые подписчики сразу получают закрытый канал.
+func (b *newsBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxFailureAttempts — после стольких неудачных попыток статья перестаёт
+// повторяться: скорее всего, страница удалена или парсер её не понимает.
+const maxFailureAttempts = 5
+
+// abandonedTTL — сколько помнить статью, от которой отказались. Пока запись
+// жива, статья не загружается, даже если всё ещё есть на странице списка;
+// после истечения она получает новую серию попыток.
+const abandonedTTL = 7 * 24 * time.Hour
+
+type failedItem struct {
+	digest      entity.NewsDigest
+	reason      string
+	attempts    int
+	lastAttempt time.Time
+	abandoned   bool
+}
+
+// failureLog хранит статьи источника, которые не удалось загрузить или
+// сохранить, чтобы повторить их на следующем запуске, а также статьи,
+// от которых отказались после maxFailureAttempts попыток.
+type failureLog struct {
+	mu    sync.Mutex
+	items map[string]*failedItem
+}
+
+func newFailureLog() *failureLog {
+	return &failureLog{items: make(map[string]*failedItem)}
+}
+
+// record отмечает неудачу; dropped = true, если попытки исчерпаны: статья
+// остаётся в журнале отменённой и не загружается до истечения abandonedTTL.
+func (f *failureLog) record(digest entity.NewsDigest, reason error) (attempts int, dropped bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[digest.Link]
+	if !ok {
+		item = &failedItem{}
+		f.items[digest.Link] = item
+	}
+	item.digest = digest
+	item.reason = reason.Error()
+	item.attempts++
+	item.lastAttempt = time.Now()
+
+	if item.attempts >= maxFailureAttempts {
+		item.abandoned = true
+		return item.attempts, true
+	}
+	return item.attempts, false
+}
+
+func (f *failureLog) resolve(link string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.items, link)
+}
+
+// abandoned сообщает, что от статьи отказались и загружать её не нужно.
+func (f *failureLog) abandoned(link string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item, ok := f.items[link]
+	return ok && item.abandoned
+}
+
+// pending возвращает дайджесты для повтора, которых нет среди seen
+// (статьи, всё ещё присутствующие на странице списка, повторятся сами).
+// Заодно забывает отменённые статьи старше abandonedTTL.
+func (f *failureLog) pending(seen map[string]struct{}) []entity.NewsDigest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var digests []entity.NewsDigest
+	for link, item := range f.items {
+		if item.abandoned {
+			if time.Since(item.lastAttempt) > abandonedTTL {
+				delete(f.items, link)
+			}
+			continue
+		}
+		if _, ok := seen[link]; !ok {
+			digests = append(digests, item.digest)
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return digests[i].PublishedAt.After(digests[j].PublishedAt)
+	})
+	return digests
+}
+
+func (f *failureLog) list(source string) []entity.ScrapeFailure {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	failures := make([]entity.ScrapeFailure, 0, len(f.items))
+	for link, item := range f.items {
+		failures = append(failures, entity.ScrapeFailure{
+			Source:      source,
+			Link:        link,
+			Title:       item.digest.Title,
+			Reason:      item.reason,
+			Attempts:    item.attempts,
+			LastAttempt: item.lastAttempt,
+			Abandoned:   item.abandoned,
+		})
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].LastAttempt.After(failures[j].LastAttempt)
+	})
+	return failures
+}
+
+// size — число статей, ожидающих повтора, без отменённых.
+func (f *failureLog) size() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	size := 0
+	for _, item := range f.items {
+		if !item.abandoned {
+			size++
+		}
+	}
+	return size
+}
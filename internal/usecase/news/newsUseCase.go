@@ -1,80 +1,192 @@
 package usecase
 
 import (
+	"AIChallengeNewsAPI/internal/config"
 	"AIChallengeNewsAPI/internal/entity"
 	"AIChallengeNewsAPI/internal/interfaces"
-	"AIChallengeNewsAPI/internal/repository"
+	"AIChallengeNewsAPI/internal/lib/metrics"
 	"AIChallengeNewsAPI/internal/usecase/news/parsers"
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"log/slog"
-	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// finishRunAttempts — сколько раз пробовать записать итог запуска.
+const finishRunAttempts = 3
+
+// finishRunRetryDelay — пауза между попытками; переменная, чтобы тесты не ждали.
+var finishRunRetryDelay = time.Second
+
 type NewsUseCase struct {
-	log          *slog.Logger
-	repo         *repository.Repository
-	numberNews   int
-	interval     time.Duration
-	urls         []string
-	parsersArray map[string]interfaces.Parser
-	stopChan     chan struct{}
+	log        *slog.Logger
+	repo       interfaces.RepositoryInter
+	numberNews int
+	sources    []*newsSource
+	staleAfter time.Duration
+	timeouts   stageTimeouts
+	revisions  revisionSettings
+	// staleThreshold — сколько источник может не обновляться успешно,
+	// прежде чем /status пометит его устаревшим.
+	staleThreshold time.Duration
+	startedAt      time.Time
+	fetchSlots     chan struct{}
+	queueDepth     atomic.Int64
+	broker         *newsBroker
+	stopChan       chan struct{}
+	stopOnce       sync.Once
+}
+
+// stageTimeouts — дедлайны этапов одного запуска источника.
+type stageTimeouts struct {
+	listing time.Duration
+	article time.Duration
+	store   time.Duration
+	run     time.Duration
+}
+
+type newsSource struct {
+	name        string
+	urls        []string
+	schedule    schedule
+	concurrency int
+	parser      interfaces.Parser
+	parserType  string
+	queueDepth  atomic.Int64
+	failures    *failureLog
+	running     atomic.Bool
+	state       sourceState
 }
 
-func NewNewsUseCase(log *slog.Logger, repo *repository.Repository, numberNews int, interval time.Duration) (*NewsUseCase, error) {
-	var urls = []string{
-		"https://ru.investing.com/news/",
-		"https://www.finmarket.ru/news/",
+func NewNewsUseCase(log *slog.Logger, repo interfaces.RepositoryInter, numberNews int, scraperConfig config.ScraperConfig,
+	sourcesConfig []config.SourceConfig) (*NewsUseCase, error) {
+
+	var sources []*newsSource
+	for _, sourceConfig := range sourcesConfig {
+		if !sourceConfig.IsEnabled() {
+			log.Info("source disabled", slog.String("source", sourceConfig.Name))
+			continue
+		}
+
+		parser, err := parsers.NewParser(sourceConfig, scraperConfig, log)
+		if err != nil {
+			return nil, err
+		}
+
+		sourceInterval := sourceConfig.Interval
+		if sourceInterval == 0 {
+			sourceInterval = scraperConfig.Interval
+		}
+		sourceSchedule, err := newSchedule(sourceConfig.Schedule, sourceInterval)
+		if err != nil {
+			return nil, fmt.Errorf("source %q: %w", sourceConfig.Name, err)
+		}
+		concurrency := sourceConfig.Concurrency
+		if concurrency == 0 {
+			concurrency = scraperConfig.SourceConcurrency
+		}
+
+		sources = append(sources, &newsSource{
+			name:        sourceConfig.Name,
+			urls:        sourceConfig.URLs,
+			schedule:    sourceSchedule,
+			concurrency: concurrency,
+			parser:      parser,
+			parserType:  sourceConfig.Parser,
+			failures:    newFailureLog(),
+		})
 	}
-	var parsersArray = map[string]interfaces.Parser{
-		"ru.investing.com": parsers.NewInvestingComParser(log),
-		"www.finmarket.ru": parsers.NewFinmarketComParser(log),
+	if len(sources) == 0 {
+		log.Warn("no enabled news sources configured")
 	}
 
 	return &NewsUseCase{
-		log:          log,
-		repo:         repo,
-		numberNews:   numberNews,
-		interval:     interval,
-		urls:         urls,
-		parsersArray: parsersArray,
+		log:        log,
+		repo:       repo,
+		numberNews: numberNews,
+		sources:    sources,
+		staleAfter: scraperConfig.RunStaleAfter,
+		timeouts: stageTimeouts{
+			listing: scraperConfig.ListingTimeout,
+			article: scraperConfig.ArticleTimeout,
+			store:   scraperConfig.StoreTimeout,
+			run:     scraperConfig.RunTimeout,
+		},
+		revisions: revisionSettings{
+			window:   scraperConfig.RevisionWindow,
+			interval: scraperConfig.RevisionInterval,
+			batch:    scraperConfig.RevisionBatch,
+		},
+		staleThreshold: scraperConfig.SourceStaleAfter,
+		startedAt:      time.Now(),
+		fetchSlots:     make(chan struct{}, scraperConfig.MaxConcurrency),
+		broker:         newNewsBroker(),
+		stopChan:       make(chan struct{}),
 	}, nil
 }
 
-func (ucNews *NewsUseCase) Start() {
-	ticker := time.NewTicker(ucNews.interval)
-
-	err := ucNews.scrapeAndStoreNews()
-	if err != nil {
-		ucNews.log.Warn("failed to scrape and store new news data", slog.String("error", err.Error()))
-		ucNews.Stop()
+// Start запускает для каждого источника свой цикл парсинга по его расписанию
+// и блокируется до Stop или отмены ctx. Первый запуск каждого источника —
+// сразу при старте. Stop только прекращает планирование новых запусков,
+// отмена ctx прерывает и текущие: HTTP-запросы и запросы к базе.
+func (ucNews *NewsUseCase) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, source := range ucNews.sources {
+		wg.Add(1)
+		go func(source *newsSource) {
+			defer wg.Done()
+			ucNews.runSource(ctx, source)
+		}(source)
 	}
+	wg.Wait()
+}
 
+func (ucNews *NewsUseCase) runSource(ctx context.Context, source *newsSource) {
 	for {
-		select {
-		case <-ticker.C:
-			err := ucNews.scrapeAndStoreNews()
-			if err != nil {
-				ucNews.log.Warn("failed to scrape and store new news data", slog.String("error", err.Error()))
-				ucNews.Stop()
-			}
-			break
+		started := time.Now()
+		ucNews.scrapeAndStoreNews(ctx, source)
 
+		next := source.schedule.Next(started)
+		if next.IsZero() {
+			// Расписание больше не срабатывает: таймер на нулевое время
+			// сработал бы сразу, и источник опрашивался бы без остановки.
+			ucNews.log.Error("source schedule has no next run, stopping", slog.String("source", source.name))
+			return
+		}
+		ucNews.log.Info("next scrape scheduled", slog.String("source", source.name), slog.Time("at", next))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
 		case <-ucNews.stopChan:
+			timer.Stop()
+			return
+		case <-ctx.Done():
+			timer.Stop()
 			return
 		}
 	}
 }
 
+// Stop прекращает планирование новых запусков и закрывает потоки SSE.
+// Текущие запуски доводятся до конца; Start вернётся, когда они завершатся.
 func (ucNews *NewsUseCase) Stop() {
-	ucNews.log.Info("stopping news useCase")
-	ucNews.stopChan <- struct{}{}
+	ucNews.stopOnce.Do(func() {
+		ucNews.log.Info("stopping news useCase")
+		close(ucNews.stopChan)
+		ucNews.broker.Close()
+	})
 }
 
-func (ucNews *NewsUseCase) GetLatestNews(numberNews int) ([]entity.News, error) {
-	news, err := ucNews.repo.GetLatestNews(numberNews)
+func (ucNews *NewsUseCase) GetLatestNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = ucNews.numberNews
+	}
+
+	news, err := ucNews.repo.GetLatestNews(ctx, filter)
 	if err != nil {
 		ucNews.log.Warn("failed to get latest news", slog.String("error", err.Error()))
 		return nil, err
@@ -82,90 +194,364 @@ func (ucNews *NewsUseCase) GetLatestNews(numberNews int) ([]entity.News, error)
 	return news, nil
 }
 
-func (ucNews *NewsUseCase) scrapeAndStoreNews() error {
-	newsList, err := ucNews.getNewsFromSites()
+// GetNewsPage запрашивает на одну новость больше лимита, чтобы понять,
+// есть ли следующая страница, не делая отдельного COUNT.
+func (ucNews *NewsUseCase) GetNewsPage(ctx context.Context, filter entity.NewsFilter) (*entity.NewsPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = ucNews.numberNews
+	}
+	limit := filter.Limit
+	filter.Limit++
+
+	news, err := ucNews.GetLatestNews(ctx, filter)
 	if err != nil {
-		ucNews.log.Warn("Error scraping sites", slog.String("error", err.Error()))
-		return err
+		return nil, err
 	}
 
-	for _, newsItem := range newsList {
-		exists, err := ucNews.repo.ContainNews(newsItem.Link)
-		if err != nil {
-			ucNews.log.Warn("Error checking news existence", slog.String("error", err.Error()))
-			return err
+	page := &entity.NewsPage{Items: news}
+	if len(news) > limit {
+		page.Items = news[:limit]
+		last := page.Items[limit-1]
+		page.Next = &entity.NewsCursor{PublishedAt: last.PublishedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+func (ucNews *NewsUseCase) SearchNews(ctx context.Context, text string, filter entity.NewsFilter) ([]entity.NewsSearchResult, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = ucNews.numberNews
+	}
+
+	results, err := ucNews.repo.SearchNews(ctx, text, filter)
+	if err != nil {
+		ucNews.log.Warn("failed to search news", slog.String("query", text), slog.String("error", err.Error()))
+		return nil, err
+	}
+	return results, nil
+}
+
+// SubscribeNews подписывает на новости, сохраняемые в ходе парсинга.
+// Пропущенное до подписки дочитывается через GetNewsAfterID.
+func (ucNews *NewsUseCase) SubscribeNews() (<-chan entity.News, func()) {
+	return ucNews.broker.Subscribe()
+}
+
+// GetNewsAfterID возвращает до limit новостей с ID больше id в порядке ID.
+func (ucNews *NewsUseCase) GetNewsAfterID(ctx context.Context, id int, limit int) ([]entity.News, error) {
+	news, err := ucNews.repo.GetNewsAfterID(ctx, id, limit)
+	if err != nil {
+		ucNews.log.Warn("failed to get news after id", slog.Int("id", id), slog.String("error", err.Error()))
+		return nil, err
+	}
+	return news, nil
+}
+
+func (ucNews *NewsUseCase) GetNewsById(ctx context.Context, id int) (*entity.News, error) {
+	news, err := ucNews.repo.GetNewsById(ctx, id)
+	if err != nil {
+		if !errors.Is(err, entity.ErrNewsNotFound) {
+			ucNews.log.Warn("failed to get news by id", slog.Int("id", id), slog.String("error", err.Error()))
 		}
+		return nil, err
+	}
+	return news, nil
+}
+
+// Failures возвращает статьи, ожидающие повтора, по всем источникам.
+func (ucNews *NewsUseCase) Failures() []entity.ScrapeFailure {
+	var failures []entity.ScrapeFailure
+	for _, source := range ucNews.sources {
+		failures = append(failures, source.failures.list(source.name)...)
+	}
+	return failures
+}
+
+func (ucNews *NewsUseCase) GetScrapeRuns(ctx context.Context, filter entity.ScrapeRunFilter) ([]entity.ScrapeRun, error) {
+	runs, err := ucNews.repo.GetScrapeRuns(ctx, filter)
+	if err != nil {
+		ucNews.log.Warn("failed to get scrape runs", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return runs, nil
+}
+
+// scrapeAndStoreNews выполняет один запуск парсинга источника и сохраняет его
+// в scrape_runs. Запуски одного источника не пересекаются: внутри процесса
+// их сериализует флаг running, между репликами — уникальный индекс в базе.
+//
+// Ошибки загрузки изолируются по отдельным статьям: неудачная статья
+// записывается в failureLog источника и повторяется в следующий раз. Загруженные
+// статьи сохраняются одной транзакцией; если она не удалась, в failureLog
+// попадают все они.
+//
+// После сохранения новых статей недавние уже сохранённые перепроверяются
+// на изменения (см. checkRevisions).
+//
+// Весь запуск ограничен timeouts.run; если он прерван отменой ctx, уже
+// загруженные статьи всё равно сохраняются, запуск записывается с ошибкой,
+// а недокачанные статьи подхватит следующий запуск.
+func (ucNews *NewsUseCase) scrapeAndStoreNews(ctx context.Context, source *newsSource) {
+	if !source.running.CompareAndSwap(false, true) {
+		ucNews.log.Info("scrape run already in progress, skipping", slog.String("source", source.name))
+		return
+	}
+	defer source.running.Store(false)
+
+	ctx, cancel := context.WithTimeout(ctx, ucNews.timeouts.run)
+	defer cancel()
 
-		if !exists {
-			err = ucNews.repo.AddNews(newsItem)
-			if err != nil {
-				ucNews.log.Warn("Error adding news", slog.String("error", err.Error()))
-				return err
+	storeCtx, storeCancel := context.WithTimeout(ctx, ucNews.timeouts.store)
+	run, err := ucNews.repo.StartScrapeRun(storeCtx, source.name, ucNews.staleAfter)
+	storeCancel()
+	if errors.Is(err, entity.ErrRunInProgress) {
+		ucNews.log.Info("scrape run already in progress, skipping", slog.String("source", source.name))
+		return
+	}
+	if err != nil {
+		// История запусков — вспомогательная: без неё парсинг всё равно выполняется.
+		ucNews.log.Warn("failed to register scrape run", slog.String("source", source.name),
+			slog.String("error", err.Error()))
+		run = &entity.ScrapeRun{Source: source.name, StartedAt: time.Now()}
+	}
+
+	newsList := ucNews.getNewsFromSource(ctx, source, run)
+
+	if len(newsList) > 0 {
+		// Уже загруженные статьи сохраняем и после отмены ctx (таймаут
+		// запуска, остановка приложения): иначе их пришлось бы качать заново.
+		inserted, err := ucNews.storeNews(context.WithoutCancel(ctx), source, newsList)
+		switch {
+		case err != nil:
+			ucNews.log.Warn("Error storing news", slog.String("source", source.name),
+				slog.Int("news", len(newsList)), slog.String("error", err.Error()))
+			for _, newsItem := range newsList {
+				ucNews.recordFailure(source, *newsItem.ConvertToNewsDigest(), err)
 			}
+			run.Failed += len(newsList)
+		default:
+			for _, newsItem := range newsList {
+				source.failures.resolve(newsItem.Link)
+			}
+			run.Inserted = len(inserted)
 		}
 	}
-	ucNews.log.Info("The parsing is over")
-	return nil
+
+	if ctx.Err() == nil {
+		ucNews.checkRevisions(ctx, source)
+	}
+
+	if err := ctx.Err(); err != nil {
+		ucNews.log.Warn("scrape run interrupted", slog.String("source", source.name), slog.String("error", err.Error()))
+		run.Error = fmt.Sprintf("run interrupted: %v", err)
+	}
+
+	// Итог запуска записываем и после отмены ctx, иначе запись осталась бы
+	// «running» до истечения staleAfter.
+	ucNews.finishRun(context.WithoutCancel(ctx), run)
+	source.state.update(*run)
 }
 
-func (ucNews *NewsUseCase) getNewsFromSites() ([]entity.News, error) {
-	var wg sync.WaitGroup
-	newsChannel := make(chan []entity.News)
+func (ucNews *NewsUseCase) finishRun(ctx context.Context, run *entity.ScrapeRun) {
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
 
-	for _, url := range ucNews.urls {
-		wg.Add(1)
+	switch {
+	case run.Error != "" && run.Digests == 0:
+		run.Status = entity.RunStatusFailed
+	case run.Error != "" || run.Failed > 0:
+		run.Status = entity.RunStatusPartial
+	default:
+		run.Status = entity.RunStatusSucceeded
+	}
 
-		go func(url string) {
-			defer wg.Done()
+	metrics.ScrapeRunDuration.WithLabelValues(run.Source, run.Status).Observe(finishedAt.Sub(run.StartedAt).Seconds())
+	metrics.ScrapeDigests.WithLabelValues(run.Source).Add(float64(run.Digests))
+	metrics.ScrapeInserted.WithLabelValues(run.Source).Add(float64(run.Inserted))
+	metrics.ScrapeFailed.WithLabelValues(run.Source).Add(float64(run.Failed))
+	metrics.ScrapeLastRunDigests.WithLabelValues(run.Source).Set(float64(run.Digests))
+	if run.Status != entity.RunStatusFailed {
+		metrics.ScrapeLastSuccess.WithLabelValues(run.Source).Set(float64(finishedAt.Unix()))
+	}
 
-			domain := ucNews.getDomainFromURL(url)
+	ucNews.log.Info("The parsing is over", slog.String("source", run.Source), slog.Int("run", run.ID),
+		slog.String("status", run.Status), slog.Int("digests", run.Digests), slog.Int("fetched", run.Fetched),
+		slog.Int("inserted", run.Inserted), slog.Int("failed", run.Failed),
+		slog.Duration("duration", finishedAt.Sub(run.StartedAt)))
 
-			parser, exists := ucNews.parsersArray[domain]
-			if !exists {
-				ucNews.log.Warn("Парсер для домена не найден", slog.String("domain", domain))
-				return
-			}
+	if run.ID == 0 {
+		return
+	}
+	ucNews.saveFinishedRun(ctx, run)
+}
 
-			newsDigests, err := ucNews.getNewsDigestFromSite(url, parser)
-			if err != nil {
-				ucNews.log.Warn("Ошибка получения digests", slog.String("error", err.Error()))
+// saveFinishedRun записывает итог запуска, повторяя при ошибке: незаписанный
+// запуск остался бы в истории «running» до истечения staleAfter и не дал бы
+// другим репликам запустить источник.
+func (ucNews *NewsUseCase) saveFinishedRun(ctx context.Context, run *entity.ScrapeRun) {
+	var err error
+	for attempt := 1; attempt <= finishRunAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(finishRunRetryDelay):
+			case <-ctx.Done():
 				return
 			}
+		}
 
-			news, err := ucNews.getNewsFromNewsDigest(newsDigests, parser)
-			if err != nil {
-				ucNews.log.Warn("Ошибка получения news", slog.String("error", err.Error()))
-				return
+		storeCtx, cancel := context.WithTimeout(ctx, ucNews.timeouts.store)
+		err = ucNews.repo.FinishScrapeRun(storeCtx, *run)
+		cancel()
+		if err == nil {
+			return
+		}
+		ucNews.log.Warn("failed to save scrape run", slog.String("source", run.Source), slog.Int("run", run.ID),
+			slog.Int("attempt", attempt), slog.String("error", err.Error()))
+	}
+
+	metrics.ScrapeRunFinishErrors.WithLabelValues(run.Source).Inc()
+	ucNews.log.Error("scrape run left unfinished", slog.String("source", run.Source), slog.Int("run", run.ID),
+		slog.Duration("stale_after", ucNews.staleAfter), slog.String("error", err.Error()))
+}
+
+// storeNews сохраняет новости одной транзакцией и рассылает подписчикам
+// только действительно новые: уже известные ссылки база пропускает сама,
+// поэтому гонки между репликами не приводят к дубликатам.
+func (ucNews *NewsUseCase) storeNews(ctx context.Context, source *newsSource, newsList []entity.News) ([]entity.News, error) {
+	for i := range newsList {
+		newsList[i].SourceKey = source.name
+		newsList[i].ContentHash = entity.ContentHash(newsList[i].Title, newsList[i].Text)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ucNews.timeouts.store)
+	defer cancel()
+
+	inserted, err := ucNews.repo.UpsertNewsBatch(ctx, newsList)
+	if err != nil {
+		return nil, fmt.Errorf("error storing news: %w", err)
+	}
+	for _, newsItem := range inserted {
+		ucNews.broker.Publish(newsItem)
+	}
+	return inserted, nil
+}
+
+func (ucNews *NewsUseCase) recordFailure(source *newsSource, digest entity.NewsDigest, err error) {
+	attempts, dropped := source.failures.record(digest, err)
+	if dropped {
+		ucNews.log.Warn("giving up on news after repeated failures", slog.String("source", source.name),
+			slog.String("url", digest.Link), slog.Int("attempts", attempts))
+	}
+}
+
+// getNewsFromSource собирает дайджесты со всех страниц списка источника
+// (ошибка одной страницы не мешает остальным), добавляет статьи, ожидающие
+// повтора, и загружает их.
+func (ucNews *NewsUseCase) getNewsFromSource(ctx context.Context, source *newsSource, run *entity.ScrapeRun) []entity.News {
+	seen := make(map[string]struct{})
+	var digests []entity.NewsDigest
+
+	for _, url := range source.urls {
+		if ctx.Err() != nil {
+			return nil
+		}
+		newsDigests, err := ucNews.getNewsDigestFromSite(ctx, url, source)
+		if err != nil {
+			ucNews.log.Warn("Ошибка получения digests", slog.String("source", source.name),
+				slog.String("url", url), slog.String("error", err.Error()))
+			run.Error = err.Error()
+			continue
+		}
+		for _, digest := range newsDigests {
+			if _, ok := seen[digest.Link]; ok {
+				continue
 			}
+			seen[digest.Link] = struct{}{}
+			digests = append(digests, digest)
+		}
+	}
 
-			newsChannel <- news
-		}(url)
+	digests = append(digests, source.failures.pending(seen)...)
+	run.Digests = len(digests)
+
+	fresh := ucNews.filterNewDigests(ctx, digests, source)
+	news := ucNews.getNewsFromNewsDigest(ctx, fresh, source)
+	run.Fetched = len(news)
+	run.Failed += len(fresh) - len(news)
+	return news
+}
+
+// filterNewDigests одним запросом отбрасывает уже сохранённые статьи, чтобы
+// не скачивать их страницы повторно. Если проверка не удалась, загружаем всё:
+// дубликаты всё равно отсеются при сохранении. Статьи, от которых отказались
+// после maxFailureAttempts попыток, отбрасываются всегда.
+func (ucNews *NewsUseCase) filterNewDigests(ctx context.Context, digests []entity.NewsDigest, source *newsSource) []entity.NewsDigest {
+	candidates := make([]entity.NewsDigest, 0, len(digests))
+	for _, digest := range digests {
+		if !source.failures.abandoned(digest.Link) {
+			candidates = append(candidates, digest)
+		}
 	}
+	digests = candidates
 
-	go func() {
-		wg.Wait()
-		close(newsChannel)
-	}()
+	links := make([]string, len(digests))
+	for i, digest := range digests {
+		links[i] = digest.Link
+	}
 
-	newsArray := make([]entity.News, 0)
-	for newsBatch := range newsChannel {
-		for _, news := range newsBatch {
-			newsArray = append(newsArray, news)
+	ctx, cancel := context.WithTimeout(ctx, ucNews.timeouts.store)
+	defer cancel()
+
+	existing, err := ucNews.repo.ExistingUrls(ctx, links)
+	if err != nil {
+		ucNews.log.Warn("failed to check stored news", slog.String("source", source.name),
+			slog.String("error", err.Error()))
+		return digests
+	}
+
+	fresh := make([]entity.NewsDigest, 0, len(digests))
+	for _, digest := range digests {
+		if _, ok := existing[digest.Link]; ok {
+			source.failures.resolve(digest.Link)
+			continue
 		}
+		fresh = append(fresh, digest)
+	}
+
+	ucNews.log.Info("news digests checked", slog.String("source", source.name),
+		slog.Int("digests", len(digests)), slog.Int("new", len(fresh)))
+	return fresh
+}
+
+// acquireFetchSlot ограничивает число одновременных загрузок по всем источникам.
+// Ожидание слота прерывается отменой ctx.
+func (ucNews *NewsUseCase) acquireFetchSlot(ctx context.Context) (func(), error) {
+	select {
+	case ucNews.fetchSlots <- struct{}{}:
+		return func() { <-ucNews.fetchSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	return newsArray, nil
 }
 
-func (ucNews *NewsUseCase) getNewsDigestFromSite(url string, parser interfaces.Parser) ([]entity.NewsDigest, error) {
-	html, err := parser.FetchHTML(url)
+func (ucNews *NewsUseCase) getNewsDigestFromSite(ctx context.Context, url string, source *newsSource) ([]entity.NewsDigest, error) {
+	ctx, cancel := context.WithTimeout(ctx, ucNews.timeouts.listing)
+	defer cancel()
+
+	release, err := ucNews.acquireFetchSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	html, err := source.parser.FetchHTML(ctx, url)
+	release()
 	if err != nil {
 		ucNews.log.Warn("Ошибка получения HTML", slog.String("error", err.Error()),
 			slog.String("url", url))
 		return nil, fmt.Errorf("error fetching HTML: %v", err)
 	}
 
-	news, err := parser.ParseNewsDigest(html)
+	news, err := source.parser.ParseNewsDigest(ctx, url, html)
 	if err != nil {
+		metrics.ParseErrors.WithLabelValues(source.name, source.parserType, "digest").Inc()
 		ucNews.log.Warn("Ошибка парсинга для урла", slog.String("error", err.Error()),
 			slog.String("url", url))
 		return nil, fmt.Errorf("error parsing HTML: %v", err)
@@ -173,32 +559,84 @@ func (ucNews *NewsUseCase) getNewsDigestFromSite(url string, parser interfaces.P
 	return news, nil
 }
 
-func (ucNews *NewsUseCase) getNewsFromNewsDigest(newsDigest []entity.NewsDigest, parser interfaces.Parser) ([]entity.News, error) {
-	var newsArray []entity.News
-	for _, newsItem := range newsDigest {
-		html, err := parser.FetchHTML(newsItem.Link)
-		if err != nil {
-			ucNews.log.Warn("Ошибка получения HTML", slog.String("error", err.Error()),
-				slog.String("url", newsItem.Link))
-			return nil, fmt.Errorf("error fetching HTML: %v", err)
-		}
-		news, err := parser.ParseNews(html, newsItem)
-		if err != nil {
-			ucNews.log.Warn("Ошибка парсинга новостей", slog.String("error", err.Error()))
-			return nil, fmt.Errorf("error parsing HTML: %v", err)
-		}
+// getNewsFromNewsDigest загружает статьи источника пулом из source.concurrency
+// воркеров. Результаты складываются по индексу дайджеста, поэтому порядок
+// новостей совпадает с порядком на странице списка. Неудачные статьи
+// попадают в failureLog источника и не мешают остальным. После отмены ctx
+// оставшиеся статьи пропускаются и в failureLog не попадают.
+func (ucNews *NewsUseCase) getNewsFromNewsDigest(ctx context.Context, newsDigest []entity.NewsDigest, source *newsSource) []entity.News {
+	results := make([]*entity.News, len(newsDigest))
 
-		newsArray = append(newsArray, *news)
+	var wg sync.WaitGroup
+	jobs := make(chan int)
 
+	workers := min(source.concurrency, len(newsDigest))
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() == nil {
+					news, err := ucNews.getNews(ctx, newsDigest[i], source)
+					switch {
+					case err == nil:
+						results[i] = news
+					case ctx.Err() == nil:
+						ucNews.recordFailure(source, newsDigest[i], err)
+					}
+				}
+				metrics.ScrapeQueueDepth.WithLabelValues(source.name).Set(float64(source.queueDepth.Add(-1)))
+				ucNews.queueDepth.Add(-1)
+			}
+		}()
 	}
-	return newsArray, nil
+
+	metrics.ScrapeQueueDepth.WithLabelValues(source.name).Set(float64(source.queueDepth.Add(int64(len(newsDigest)))))
+	ucNews.queueDepth.Add(int64(len(newsDigest)))
+	ucNews.log.Debug("article queue", slog.String("source", source.name),
+		slog.Int64("source_depth", source.queueDepth.Load()), slog.Int64("total_depth", ucNews.queueDepth.Load()))
+
+	for i := range newsDigest {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	newsArray := make([]entity.News, 0, len(newsDigest))
+	for _, news := range results {
+		if news != nil {
+			newsArray = append(newsArray, *news)
+		}
+	}
+	return newsArray
 }
 
-func (ucNews *NewsUseCase) getDomainFromURL(userUrl string) string {
-	u, err := url.Parse(userUrl)
+func (ucNews *NewsUseCase) getNews(ctx context.Context, newsItem entity.NewsDigest, source *newsSource) (*entity.News, error) {
+	parser := source.parser
+	if digestParser, ok := parser.(interfaces.DigestOnlyParser); ok && !digestParser.FetchesArticles() {
+		return digestParser.NewsFromDigest(newsItem), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ucNews.timeouts.article)
+	defer cancel()
+
+	release, err := ucNews.acquireFetchSlot(ctx)
 	if err != nil {
-		log.Printf("Ошибка при парсинге URL: %v", err)
-		return ""
+		return nil, err
 	}
-	return u.Host
+	html, err := parser.FetchHTML(ctx, newsItem.Link)
+	release()
+	if err != nil {
+		ucNews.log.Warn("Ошибка получения HTML", slog.String("error", err.Error()),
+			slog.String("url", newsItem.Link))
+		return nil, fmt.Errorf("error fetching HTML: %v", err)
+	}
+
+	news, err := parser.ParseNews(ctx, html, newsItem)
+	if err != nil {
+		metrics.ParseErrors.WithLabelValues(source.name, source.parserType, "article").Inc()
+		ucNews.log.Warn("Ошибка парсинга новостей", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("error parsing HTML: %v", err)
+	}
+	return news, nil
 }
@@ -0,0 +1,570 @@
+package usecase
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/repository/memory"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testScraperConfig() config.ScraperConfig {
+	return config.ScraperConfig{
+		Interval:          time.Hour,
+		HTTPTimeout:       5 * time.Second,
+		MaxBodySize:       1 << 20,
+		SourceConcurrency: 2,
+		MaxConcurrency:    4,
+		RunStaleAfter:     time.Hour,
+		SourceStaleAfter:  time.Hour,
+		ListingTimeout:    5 * time.Second,
+		ArticleTimeout:    5 * time.Second,
+		StoreTimeout:      5 * time.Second,
+		RunTimeout:        time.Minute,
+		RevisionWindow:    48 * time.Hour,
+		RevisionInterval:  time.Nanosecond,
+		RevisionBatch:     20,
+	}
+}
+
+func newTestUseCase(t *testing.T, repo *memory.Repository, sources ...config.SourceConfig) *NewsUseCase {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ucNews, err := NewNewsUseCase(log, repo, 10, testScraperConfig(), sources)
+	if err != nil {
+		t.Fatalf("NewNewsUseCase: %v", err)
+	}
+	return ucNews
+}
+
+// testSite — сайт из страницы списка со ссылкой на одну статью. Провайдер
+// в списке отличается от имени источника, как у investing.com.
+type testSite struct {
+	mu       sync.Mutex
+	text     string
+	requests int
+}
+
+func (site *testSite) setText(text string) {
+	site.mu.Lock()
+	defer site.mu.Unlock()
+	site.text = text
+}
+
+func (site *testSite) requestCount() int {
+	site.mu.Lock()
+	defer site.mu.Unlock()
+	return site.requests
+}
+
+func (site *testSite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	site.mu.Lock()
+	defer site.mu.Unlock()
+	site.requests++
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	switch r.URL.Path {
+	case "/news":
+		fmt.Fprint(w, `<html><body><div class="item"><a href="/news/1">Ставка ЦБ</a><span class="provider">Интерфакс</span></div></body></html>`)
+	case "/news/1":
+		fmt.Fprintf(w, `<html><body><article><p>%s</p></article></body></html>`, site.text)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func selectorSource(name, baseURL string) config.SourceConfig {
+	return config.SourceConfig{
+		Name:   name,
+		URLs:   []string{baseURL + "/news"},
+		Parser: "selector",
+		Selector: config.SelectorConfig{
+			Item:     "div.item",
+			Link:     "a",
+			Title:    "a",
+			Provider: ".provider",
+			Body:     "article p",
+		},
+	}
+}
+
+// findNews ищет сохранённую новость по ссылке среди последних.
+func findNews(t *testing.T, repo *memory.Repository, link string) (entity.News, bool) {
+	t.Helper()
+
+	newsList, err := repo.GetLatestNews(context.Background(), entity.NewsFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetLatestNews: %v", err)
+	}
+	for _, news := range newsList {
+		if news.Link == link {
+			return news, true
+		}
+	}
+	return entity.News{}, false
+}
+
+func seedNews(t *testing.T, repo *memory.Repository, news ...entity.News) []entity.News {
+	t.Helper()
+
+	inserted, err := repo.UpsertNewsBatch(context.Background(), news)
+	if err != nil {
+		t.Fatalf("UpsertNewsBatch: %v", err)
+	}
+	return inserted
+}
+
+func TestGetNewsPageWalksAllPages(t *testing.T) {
+	repo := memory.NewRepository()
+	base := time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC)
+
+	// Две пары новостей с одинаковым временем: порядок внутри пары задаёт ID.
+	var news []entity.News
+	for i, offset := range []time.Duration{0, 0, -time.Hour, -2 * time.Hour, -2 * time.Hour, -3 * time.Hour, -4 * time.Hour} {
+		news = append(news, entity.News{
+			Title:       fmt.Sprintf("Новость %d", i+1),
+			Link:        fmt.Sprintf("https://example.com/news/%d", i+1),
+			Source:      "example.com",
+			PublishedAt: base.Add(offset),
+		})
+	}
+	seedNews(t, repo, news...)
+
+	ucNews := newTestUseCase(t, repo)
+	ctx := context.Background()
+
+	var got []int
+	filter := entity.NewsFilter{Limit: 3}
+	for pages := 0; ; pages++ {
+		if pages > len(news) {
+			t.Fatal("pagination does not terminate")
+		}
+		page, err := ucNews.GetNewsPage(ctx, filter)
+		if err != nil {
+			t.Fatalf("GetNewsPage: %v", err)
+		}
+		if len(page.Items) > filter.Limit {
+			t.Fatalf("page has %d items, limit %d", len(page.Items), filter.Limit)
+		}
+		for _, item := range page.Items {
+			got = append(got, item.ID)
+		}
+		if page.Next == nil {
+			break
+		}
+		filter.After = page.Next
+	}
+
+	want := []int{2, 1, 3, 5, 4, 6, 7}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ids across pages = %v, want %v", got, want)
+	}
+}
+
+func TestGetNewsPageLastPageHasNoCursor(t *testing.T) {
+	repo := memory.NewRepository()
+	seedNews(t, repo,
+		entity.News{Title: "a", Link: "https://example.com/a", PublishedAt: time.Now()},
+		entity.News{Title: "b", Link: "https://example.com/b", PublishedAt: time.Now()},
+	)
+
+	page, err := newTestUseCase(t, repo).GetNewsPage(context.Background(), entity.NewsFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetNewsPage: %v", err)
+	}
+	if len(page.Items) != 2 || page.Next != nil {
+		t.Errorf("got %d items and cursor %v, want 2 items and no cursor", len(page.Items), page.Next)
+	}
+}
+
+func TestSearchNews(t *testing.T) {
+	repo := memory.NewRepository()
+	now := time.Now()
+	seedNews(t, repo,
+		entity.News{Title: "Ключевая ставка сохранена", Link: "https://example.com/1", Source: "a", Text: "Решение ЦБ.", PublishedAt: now},
+		entity.News{Title: "Рынок акций", Link: "https://example.com/2", Source: "b", Text: "Инвесторы ждут решения по ставке.", PublishedAt: now},
+		entity.News{Title: "Ставка по ипотеке", Link: "https://example.com/3", Source: "a", Text: "Льготная ипотека.", PublishedAt: now.Add(-time.Hour)},
+		entity.News{Title: "Нефть", Link: "https://example.com/4", Source: "a", Text: "Brent подорожала.", PublishedAt: now},
+	)
+	ucNews := newTestUseCase(t, repo)
+
+	tests := []struct {
+		name   string
+		query  string
+		filter entity.NewsFilter
+		want   []string
+	}{
+		// При равном весе новые идут первыми.
+		{name: "title match ranks first", query: "ставк", want: []string{"https://example.com/1", "https://example.com/3", "https://example.com/2"}},
+		{name: "excluded word", query: "ставк -ипотек", want: []string{"https://example.com/1", "https://example.com/2"}},
+		{name: "phrase", query: `"ключевая ставка"`, want: []string{"https://example.com/1"}},
+		{name: "source filter", query: "ставк", filter: entity.NewsFilter{Sources: []string{"b"}}, want: []string{"https://example.com/2"}},
+		{name: "limit", query: "ставк", filter: entity.NewsFilter{Limit: 1}, want: []string{"https://example.com/1"}},
+		{name: "no match", query: "золото", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := ucNews.SearchNews(context.Background(), tt.query, tt.filter)
+			if err != nil {
+				t.Fatalf("SearchNews: %v", err)
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, result.Link)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("SearchNews(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrapeSkipsRunInProgressInProcess(t *testing.T) {
+	site := &testSite{text: "Текст."}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	repo := memory.NewRepository()
+	ucNews := newTestUseCase(t, repo, selectorSource("testsite", server.URL))
+	source := ucNews.sources[0]
+
+	source.running.Store(true)
+	ucNews.scrapeAndStoreNews(context.Background(), source)
+
+	if n := site.requestCount(); n != 0 {
+		t.Errorf("overlapping run made %d requests, want 0", n)
+	}
+	runs, _ := repo.GetScrapeRuns(context.Background(), entity.ScrapeRunFilter{})
+	if len(runs) != 0 {
+		t.Errorf("overlapping run was recorded: %+v", runs)
+	}
+}
+
+func TestScrapeSkipsRunInProgressInAnotherReplica(t *testing.T) {
+	site := &testSite{text: "Текст."}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	repo := memory.NewRepository()
+	ucNews := newTestUseCase(t, repo, selectorSource("testsite", server.URL))
+	source := ucNews.sources[0]
+	ctx := context.Background()
+
+	// Запуск другой реплики, ещё не завершённый.
+	if _, err := repo.StartScrapeRun(ctx, source.name, time.Hour); err != nil {
+		t.Fatalf("StartScrapeRun: %v", err)
+	}
+	if _, err := repo.StartScrapeRun(ctx, source.name, time.Hour); !errors.Is(err, entity.ErrRunInProgress) {
+		t.Fatalf("second StartScrapeRun error = %v, want ErrRunInProgress", err)
+	}
+
+	ucNews.scrapeAndStoreNews(ctx, source)
+
+	if n := site.requestCount(); n != 0 {
+		t.Errorf("overlapping run made %d requests, want 0", n)
+	}
+	runs, _ := repo.GetScrapeRuns(ctx, entity.ScrapeRunFilter{Source: source.name})
+	if len(runs) != 1 || runs[0].Status != entity.RunStatusRunning {
+		t.Errorf("runs = %+v, want only the other replica's running run", runs)
+	}
+	if source.running.Load() {
+		t.Error("skipped run left the source marked as running")
+	}
+}
+
+func TestStoreNewsSkipsKnownLinks(t *testing.T) {
+	repo := memory.NewRepository()
+	ucNews := newTestUseCase(t, repo)
+	source := &newsSource{name: "testsite", failures: newFailureLog()}
+	ctx := context.Background()
+
+	events, cancel := ucNews.SubscribeNews()
+	defer cancel()
+
+	now := time.Now()
+	first := []entity.News{
+		{Title: "a", Link: "https://example.com/a", PublishedAt: now},
+		{Title: "b", Link: "https://example.com/b", PublishedAt: now},
+	}
+	inserted, err := ucNews.storeNews(ctx, source, first)
+	if err != nil {
+		t.Fatalf("storeNews: %v", err)
+	}
+	if len(inserted) != 2 {
+		t.Fatalf("inserted %d news, want 2", len(inserted))
+	}
+
+	// Повтор уже сохранённой ссылки и дубликат внутри одного пакета.
+	second := []entity.News{
+		{Title: "b again", Link: "https://example.com/b", PublishedAt: now},
+		{Title: "c", Link: "https://example.com/c", PublishedAt: now},
+		{Title: "c again", Link: "https://example.com/c", PublishedAt: now},
+	}
+	inserted, err = ucNews.storeNews(ctx, source, second)
+	if err != nil {
+		t.Fatalf("storeNews: %v", err)
+	}
+	if len(inserted) != 1 || inserted[0].Link != "https://example.com/c" || inserted[0].Title != "c" {
+		t.Fatalf("inserted = %+v, want only the first https://example.com/c", inserted)
+	}
+
+	stored, err := repo.GetLatestNews(ctx, entity.NewsFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetLatestNews: %v", err)
+	}
+	if len(stored) != 3 {
+		t.Errorf("stored %d news, want 3", len(stored))
+	}
+	for _, news := range stored {
+		if news.SourceKey != source.name || news.ContentHash != entity.ContentHash(news.Title, news.Text) {
+			t.Errorf("news %s stored with source key %q and hash %q", news.Link, news.SourceKey, news.ContentHash)
+		}
+	}
+
+	// Подписчикам уходят только действительно новые новости.
+	if len(events) != 3 {
+		t.Errorf("published %d events, want 3", len(events))
+	}
+}
+
+func TestScrapeStoresArticleOnce(t *testing.T) {
+	site := &testSite{text: "Текст."}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	repo := memory.NewRepository()
+	ucNews := newTestUseCase(t, repo, selectorSource("testsite", server.URL))
+	ucNews.revisions.window = 0
+	source := ucNews.sources[0]
+	ctx := context.Background()
+
+	ucNews.scrapeAndStoreNews(ctx, source)
+	ucNews.scrapeAndStoreNews(ctx, source)
+
+	stored, err := repo.GetLatestNews(ctx, entity.NewsFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetLatestNews: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Errorf("stored %d news, want 1", len(stored))
+	}
+
+	// Второй запуск загружает только страницу списка: статья уже в базе.
+	if n := site.requestCount(); n != 3 {
+		t.Errorf("made %d requests, want 3 (list, article, list)", n)
+	}
+
+	runs, _ := repo.GetScrapeRuns(ctx, entity.ScrapeRunFilter{Source: source.name})
+	if len(runs) != 2 || runs[0].Inserted != 0 || runs[1].Inserted != 1 {
+		t.Errorf("runs = %+v, want the first run to insert 1 and the second 0", runs)
+	}
+}
+
+func TestScrapeStoresFetchedArticlesAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/news", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body>
+			<div class="item"><a href="/news/1">Первая</a></div>
+			<div class="item"><a href="/news/2">Вторая</a></div>
+		</body></html>`)
+	})
+	mux.HandleFunc("/news/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><article><p>Загружена до отмены.</p></article></body></html>`)
+	})
+	// Вторая статья отменяет запуск, пока её загружают, — как остановка
+	// приложения посреди парсинга.
+	mux.HandleFunc("/news/2", func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	repo := memory.NewRepository()
+	sourceConfig := selectorSource("testsite", server.URL)
+	sourceConfig.Concurrency = 1
+	ucNews := newTestUseCase(t, repo, sourceConfig)
+	source := ucNews.sources[0]
+
+	ucNews.scrapeAndStoreNews(ctx, source)
+
+	if _, ok := findNews(t, repo, server.URL+"/news/1"); !ok {
+		t.Error("article fetched before cancel was not stored")
+	}
+	if _, ok := findNews(t, repo, server.URL+"/news/2"); ok {
+		t.Error("interrupted article was stored")
+	}
+
+	runs, _ := repo.GetScrapeRuns(context.Background(), entity.ScrapeRunFilter{Source: source.name})
+	if len(runs) != 1 || runs[0].Inserted != 1 || runs[0].Error == "" {
+		t.Errorf("runs = %+v, want one interrupted run that inserted 1 article", runs)
+	}
+}
+
+// neverSchedule — расписание, у которого нет следующего запуска, как у
+// cron-выражения "0 0 30 2 *".
+type neverSchedule struct{}
+
+func (neverSchedule) Next(after time.Time) time.Time {
+	return time.Time{}
+}
+
+func TestRunSourceStopsWhenScheduleHasNoNextRun(t *testing.T) {
+	site := &testSite{text: "Текст."}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	repo := memory.NewRepository()
+	ucNews := newTestUseCase(t, repo, selectorSource("testsite", server.URL))
+	source := ucNews.sources[0]
+	source.schedule = neverSchedule{}
+
+	done := make(chan struct{})
+	go func() {
+		ucNews.runSource(context.Background(), source)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		ucNews.Stop()
+		t.Fatal("runSource kept running with a schedule that never fires")
+	}
+
+	runs, _ := repo.GetScrapeRuns(context.Background(), entity.ScrapeRunFilter{Source: source.name})
+	if len(runs) != 1 {
+		t.Errorf("got %d runs, want 1", len(runs))
+	}
+}
+
+func TestScrapeGivesUpOnBrokenArticle(t *testing.T) {
+	var articleRequests int
+	var mu sync.Mutex
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/news", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><div class="item"><a href="/news/1">Сломанная</a></div></body></html>`)
+	})
+	mux.HandleFunc("/news/1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		articleRequests++
+		mu.Unlock()
+		http.Error(w, "gone", http.StatusGone)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ucNews := newTestUseCase(t, memory.NewRepository(), selectorSource("testsite", server.URL))
+	source := ucNews.sources[0]
+
+	for i := 0; i < maxFailureAttempts+2; i++ {
+		ucNews.scrapeAndStoreNews(context.Background(), source)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if articleRequests != maxFailureAttempts {
+		t.Errorf("broken article requested %d times, want %d", articleRequests, maxFailureAttempts)
+	}
+
+	failures := ucNews.Failures()
+	if len(failures) != 1 || !failures[0].Abandoned || failures[0].Attempts != maxFailureAttempts {
+		t.Errorf("failures = %+v, want one abandoned article", failures)
+	}
+	if pending := source.failures.size(); pending != 0 {
+		t.Errorf("%d articles pending retry, want 0", pending)
+	}
+}
+
+func TestFailureLogForgetsAbandonedAfterTTL(t *testing.T) {
+	log := newFailureLog()
+	digest := entity.NewsDigest{Link: "https://example.com/news/1"}
+	for i := 0; i < maxFailureAttempts; i++ {
+		log.record(digest, errors.New("gone"))
+	}
+	if !log.abandoned(digest.Link) {
+		t.Fatal("article is not abandoned after maxFailureAttempts")
+	}
+
+	log.items[digest.Link].lastAttempt = time.Now().Add(-abandonedTTL - time.Minute)
+	log.pending(nil)
+
+	if log.abandoned(digest.Link) {
+		t.Error("abandoned article is still skipped after abandonedTTL")
+	}
+}
+
+// flakyFinishRepository отвечает ошибкой на первые failures вызовов FinishScrapeRun.
+type flakyFinishRepository struct {
+	*memory.Repository
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (repo *flakyFinishRepository) FinishScrapeRun(ctx context.Context, run entity.ScrapeRun) error {
+	repo.mu.Lock()
+	repo.calls++
+	fail := repo.calls <= repo.failures
+	repo.mu.Unlock()
+
+	if fail {
+		return errors.New("connection reset")
+	}
+	return repo.Repository.FinishScrapeRun(ctx, run)
+}
+
+func TestScrapeRetriesFinishScrapeRun(t *testing.T) {
+	delay := finishRunRetryDelay
+	finishRunRetryDelay = time.Millisecond
+	t.Cleanup(func() { finishRunRetryDelay = delay })
+
+	tests := []struct {
+		name       string
+		failures   int
+		wantCalls  int
+		wantStatus string
+	}{
+		{name: "succeeds on retry", failures: finishRunAttempts - 1, wantCalls: finishRunAttempts, wantStatus: entity.RunStatusSucceeded},
+		{name: "gives up", failures: finishRunAttempts, wantCalls: finishRunAttempts, wantStatus: entity.RunStatusRunning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := &testSite{text: "Текст."}
+			server := httptest.NewServer(site)
+			defer server.Close()
+
+			repo := &flakyFinishRepository{Repository: memory.NewRepository(), failures: tt.failures}
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+			ucNews, err := NewNewsUseCase(log, repo, 10, testScraperConfig(), []config.SourceConfig{selectorSource("testsite", server.URL)})
+			if err != nil {
+				t.Fatalf("NewNewsUseCase: %v", err)
+			}
+
+			ucNews.scrapeAndStoreNews(context.Background(), ucNews.sources[0])
+
+			if repo.calls != tt.wantCalls {
+				t.Errorf("FinishScrapeRun called %d times, want %d", repo.calls, tt.wantCalls)
+			}
+			runs, _ := repo.GetScrapeRuns(context.Background(), entity.ScrapeRunFilter{})
+			if len(runs) != 1 || runs[0].Status != tt.wantStatus {
+				t.Errorf("runs = %+v, want one run with status %s", runs, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package parsers
+
+import (
+	"net/url"
+	"strings"
+)
+
+// categoryFromLink берёт рубрику из пути ссылки на новость:
+// первый сегмент, отличный от "news" и от идентификатора самой статьи.
+func categoryFromLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 {
+		return ""
+	}
+
+	for _, segment := range segments[:len(segments)-1] {
+		if segment != "" && segment != "news" {
+			return segment
+		}
+	}
+	return ""
+}
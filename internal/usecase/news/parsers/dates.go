@@ -0,0 +1,44 @@
+package parsers
+
+import "strings"
+
+// russianMonths переводит русские названия месяцев в английские, чтобы
+// дату можно было разобрать через time.Parse. Полные формы идут раньше
+// сокращений: при совпадении на одной позиции strings.Replacer выбирает
+// пару, указанную первой.
+var russianMonths = newRussianMonthsReplacer([][2]string{
+	{"января", "January"}, {"январь", "January"},
+	{"февраля", "February"}, {"февраль", "February"},
+	{"марта", "March"}, {"март", "March"},
+	{"апреля", "April"}, {"апрель", "April"},
+	{"мая", "May"}, {"май", "May"},
+	{"июня", "June"}, {"июнь", "June"},
+	{"июля", "July"}, {"июль", "July"},
+	{"августа", "August"}, {"август", "August"},
+	{"сентября", "September"}, {"сентябрь", "September"},
+	{"октября", "October"}, {"октябрь", "October"},
+	{"ноября", "November"}, {"ноябрь", "November"},
+	{"декабря", "December"}, {"декабрь", "December"},
+	{"янв.", "Jan"}, {"февр.", "Feb"}, {"фев.", "Feb"}, {"мар.", "Mar"}, {"апр.", "Apr"},
+	{"июн.", "Jun"}, {"июл.", "Jul"}, {"авг.", "Aug"}, {"сент.", "Sep"}, {"сен.", "Sep"},
+	{"окт.", "Oct"}, {"нояб.", "Nov"}, {"ноя.", "Nov"}, {"дек.", "Dec"},
+	{"янв", "Jan"}, {"фев", "Feb"}, {"мар", "Mar"}, {"апр", "Apr"}, {"июн", "Jun"},
+	{"июл", "Jul"}, {"авг", "Aug"}, {"сен", "Sep"}, {"окт", "Oct"}, {"ноя", "Nov"}, {"дек", "Dec"},
+})
+
+func newRussianMonthsReplacer(pairs [][2]string) *strings.Replacer {
+	var oldnew []string
+	for _, pair := range pairs {
+		oldnew = append(oldnew, pair[0], pair[1])
+	}
+	// Те же названия с заглавной буквы ("Января"), встречаются в заголовках лент.
+	for _, pair := range pairs {
+		runes := []rune(pair[0])
+		oldnew = append(oldnew, strings.ToUpper(string(runes[0]))+string(runes[1:]), pair[1])
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+func replaceRussianMonths(date string) string {
+	return russianMonths.Replace(date)
+}
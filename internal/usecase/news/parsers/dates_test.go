@@ -0,0 +1,32 @@
+package parsers
+
+import "testing"
+
+func TestReplaceRussianMonths(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{date: "25 октября 2024, 13:30", want: "25 October 2024, 13:30"},
+		{date: "7 мая 2024", want: "7 May 2024"},
+		{date: "май 2024", want: "May 2024"},
+		{date: "5 марта", want: "5 March"},
+		{date: "март", want: "March"},
+		{date: "1 Января 2025", want: "1 January 2025"},
+		{date: "Сентябрь", want: "September"},
+		{date: "24 окт. 2024", want: "24 Oct 2024"},
+		{date: "3 сент. 2024", want: "3 Sep 2024"},
+		{date: "3 сен 2024", want: "3 Sep 2024"},
+		{date: "12 февр. 2024", want: "12 Feb 2024"},
+		{date: "12 Фев 2024", want: "12 Feb 2024"},
+		{date: "30 нояб. 2024", want: "30 Nov 2024"},
+		{date: "2024-10-25T13:30:00+03:00", want: "2024-10-25T13:30:00+03:00"},
+		{date: "25 October 2024", want: "25 October 2024"},
+	}
+
+	for _, tt := range tests {
+		if got := replaceRussianMonths(tt.date); got != tt.want {
+			t.Errorf("replaceRussianMonths(%q) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
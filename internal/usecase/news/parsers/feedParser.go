@@ -0,0 +1,176 @@
+package parsers
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/lib/fetcher"
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/mmcdole/gofeed"
+)
+
+var xmlEncodingDecl = regexp.MustCompile(`(<\?xml[^>]*encoding=)["'][^"']*["']`)
+
+// FeedParser собирает дайджест из RSS/Atom ленты. Текст статьи берётся
+// со страницы по селектору feed.body, а если он не задан — из самой ленты.
+type FeedParser struct {
+	log      *slog.Logger
+	fetcher  *fetcher.Fetcher
+	source   string
+	encoding string
+	body     string
+}
+
+func NewFeedParser(log *slog.Logger, fetcher *fetcher.Fetcher, source config.SourceConfig) (*FeedParser, error) {
+	if source.Feed.Body != "" {
+		if _, err := cascadia.ParseGroup(source.Feed.Body); err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %v", source.Feed.Body, err)
+		}
+	}
+
+	return &FeedParser{
+		log:      log,
+		fetcher:  fetcher,
+		source:   source.Name,
+		encoding: source.Encoding,
+		body:     source.Feed.Body,
+	}, nil
+}
+
+func (p *FeedParser) ParseNewsDigest(ctx context.Context, pageURL, body string) ([]entity.NewsDigest, error) {
+	p.log.Info("feed news parsing")
+
+	// Тело уже перекодировано в UTF-8, а объявление в прологе может говорить
+	// об исходной кодировке — тогда gofeed перекодировал бы его повторно.
+	if utf8.ValidString(body) {
+		body = xmlEncodingDecl.ReplaceAllString(body, `${1}"utf-8"`)
+	}
+
+	feed, err := gofeed.NewParser().ParseString(body)
+	if err != nil {
+		p.log.Warn("Ошибка при парсинге ленты", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var newsArray []entity.NewsDigest
+	for _, item := range feed.Items {
+		link := strings.TrimSpace(item.Link)
+		if link == "" {
+			link = strings.TrimSpace(item.GUID)
+		}
+		if link == "" {
+			p.log.Warn("news link not found", slog.String("title", item.Title))
+			continue
+		}
+
+		category := categoryFromLink(link)
+		if len(item.Categories) > 0 {
+			category = strings.TrimSpace(item.Categories[0])
+		}
+
+		summary := item.Content
+		if summary == "" {
+			summary = item.Description
+		}
+
+		newsArray = append(newsArray, entity.NewsDigest{
+			Title:       cleanText(item.Title),
+			Link:        link,
+			Source:      p.source,
+			Category:    category,
+			Summary:     htmlToText(summary),
+			PublishedAt: itemTime(item),
+		})
+	}
+
+	return newsArray, nil
+}
+
+func (p *FeedParser) ParseNews(ctx context.Context, body string, newsDigest entity.NewsDigest) (*entity.News, error) {
+	if p.body == "" {
+		return p.NewsFromDigest(newsDigest), nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		p.log.Warn("Ошибка при парсинге HTML", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var newsBuilder strings.Builder
+	doc.Find(p.body).Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if text == "" {
+			return
+		}
+		newsBuilder.WriteString(text)
+		newsBuilder.WriteString("\n")
+	})
+
+	news := p.NewsFromDigest(newsDigest)
+	if newsBuilder.Len() > 0 {
+		news.Text = newsBuilder.String()
+	}
+	return news, nil
+}
+
+func (p *FeedParser) FetchesArticles() bool {
+	return p.body != ""
+}
+
+func (p *FeedParser) NewsFromDigest(newsDigest entity.NewsDigest) *entity.News {
+	return &entity.News{
+		Title:       newsDigest.Title,
+		Link:        newsDigest.Link,
+		Source:      newsDigest.Source,
+		Category:    newsDigest.Category,
+		Text:        newsDigest.Summary,
+		PublishedAt: newsDigest.PublishedAt,
+	}
+}
+
+func (p *FeedParser) FetchHTML(ctx context.Context, url string) (string, error) {
+	return fetchHTML(ctx, p.fetcher, p.log, url, p.encoding)
+}
+
+func itemTime(item *gofeed.Item) time.Time {
+	if item.PublishedParsed != nil {
+		return *item.PublishedParsed
+	}
+	if item.UpdatedParsed != nil {
+		return *item.UpdatedParsed
+	}
+	return time.Now()
+}
+
+// htmlToText убирает разметку из description/content: многие ленты
+// кладут туда HTML-фрагменты.
+func htmlToText(fragment string) string {
+	if !strings.Contains(fragment, "<") {
+		return strings.TrimSpace(fragment)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return strings.TrimSpace(fragment)
+	}
+
+	var paragraphs []string
+	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+		if text := cleanText(s.Text()); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	})
+	if len(paragraphs) == 0 {
+		return cleanText(doc.Text())
+	}
+	return strings.Join(paragraphs, "\n")
+}
@@ -0,0 +1,233 @@
+package parsers
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/lib/fetcher"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestFeedParser(t *testing.T, body string) *FeedParser {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	parser, err := NewFeedParser(log, nil, config.SourceConfig{Name: "example", Feed: config.FeedConfig{Body: body}})
+	if err != nil {
+		t.Fatalf("NewFeedParser: %v", err)
+	}
+	return parser
+}
+
+// readFeed читает ленту из testdata. decode повторяет то, что делает
+// FetchHTML: перекодирует тело в UTF-8, оставляя пролог как есть.
+func readFeed(t *testing.T, name string, decode bool) string {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decode {
+		return string(body)
+	}
+
+	text, err := (&fetcher.Response{Header: http.Header{}, Body: body}).Text("")
+	if err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	return text
+}
+
+func TestFeedParserParseNewsDigest(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		name   string
+		file   string
+		decode bool
+		want   []entity.NewsDigest
+	}{
+		{
+			name:   "rss 2.0",
+			file:   "rss.xml",
+			decode: true,
+			want: []entity.NewsDigest{
+				{
+					Title:       "Банк России сохранил ключевую ставку",
+					Link:        "https://example.com/news/economy/1",
+					Source:      "example",
+					Category:    "Экономика",
+					Summary:     "Совет директоров сохранил ставку.\nСледующее заседание — в декабре.",
+					PublishedAt: time.Date(2024, 10, 25, 13, 30, 0, 0, msk),
+				},
+				{
+					Title:       "Нефть подорожала",
+					Link:        "https://example.com/news/markets/2",
+					Source:      "example",
+					Category:    "markets",
+					Summary:     "Brent превысила 75 долларов за баррель.",
+					PublishedAt: time.Date(2024, 10, 25, 10, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:   "atom",
+			file:   "atom.xml",
+			decode: true,
+			want: []entity.NewsDigest{
+				{
+					Title:       "Индекс Мосбиржи вырос",
+					Link:        "https://example.com/markets/stocks/3",
+					Source:      "example",
+					Category:    "markets",
+					Summary:     "Индекс Мосбиржи вырос на 1%.",
+					PublishedAt: time.Date(2024, 10, 25, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					Title:       "Рубль укрепился",
+					Link:        "https://example.com/markets/currency/4",
+					Source:      "example",
+					Category:    "Валюта",
+					Summary:     "Курс доллара опустился ниже 96 рублей.",
+					PublishedAt: time.Date(2024, 10, 24, 9, 15, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:   "windows-1251 prolog, body decoded to utf-8",
+			file:   "rss_cp1251.xml",
+			decode: true,
+			want: []entity.NewsDigest{
+				{
+					Title:       "Минфин разместил ОФЗ",
+					Link:        "https://example.ru/news/finance/5",
+					Source:      "example",
+					Category:    "finance",
+					Summary:     "Спрос превысил предложение.",
+					PublishedAt: time.Date(2024, 10, 24, 18, 0, 0, 0, msk),
+				},
+			},
+		},
+		{
+			name: "windows-1251 prolog, raw body",
+			file: "rss_cp1251.xml",
+			want: []entity.NewsDigest{
+				{
+					Title:       "Минфин разместил ОФЗ",
+					Link:        "https://example.ru/news/finance/5",
+					Source:      "example",
+					Category:    "finance",
+					Summary:     "Спрос превысил предложение.",
+					PublishedAt: time.Date(2024, 10, 24, 18, 0, 0, 0, msk),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := newTestFeedParser(t, "")
+
+			got, err := parser.ParseNewsDigest(context.Background(), "https://example.com/rss", readFeed(t, tt.file, tt.decode))
+			if err != nil {
+				t.Fatalf("ParseNewsDigest: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d digests, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				assertDigest(t, got[i], tt.want[i])
+			}
+		})
+	}
+}
+
+func TestFeedParserParseNewsDigestInvalidFeed(t *testing.T) {
+	parser := newTestFeedParser(t, "")
+
+	if _, err := parser.ParseNewsDigest(context.Background(), "https://example.com/rss", "<html><body>not a feed</body></html>"); err == nil {
+		t.Error("expected an error for a non-feed document")
+	}
+}
+
+func TestFeedParserNewsFromDigest(t *testing.T) {
+	publishedAt := time.Date(2024, 10, 25, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		digest entity.NewsDigest
+		want   entity.News
+	}{
+		{
+			name: "summary becomes text",
+			digest: entity.NewsDigest{
+				Title:       "Нефть подорожала",
+				Link:        "https://example.com/news/markets/2",
+				Source:      "example",
+				Category:    "markets",
+				Summary:     "Brent превысила 75 долларов за баррель.",
+				PublishedAt: publishedAt,
+			},
+			want: entity.News{
+				Title:       "Нефть подорожала",
+				Link:        "https://example.com/news/markets/2",
+				Source:      "example",
+				Category:    "markets",
+				Text:        "Brent превысила 75 долларов за баррель.",
+				PublishedAt: publishedAt,
+			},
+		},
+		{
+			name: "empty summary",
+			digest: entity.NewsDigest{
+				Title:       "Рубль укрепился",
+				Link:        "https://example.com/markets/currency/4",
+				Source:      "example",
+				PublishedAt: publishedAt,
+			},
+			want: entity.News{
+				Title:       "Рубль укрепился",
+				Link:        "https://example.com/markets/currency/4",
+				Source:      "example",
+				PublishedAt: publishedAt,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTestFeedParser(t, "").NewsFromDigest(tt.digest)
+			if *got != tt.want {
+				t.Errorf("NewsFromDigest() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedParserFetchesArticles(t *testing.T) {
+	if newTestFeedParser(t, "").FetchesArticles() {
+		t.Error("parser without feed.body must not fetch articles")
+	}
+	if !newTestFeedParser(t, "article p").FetchesArticles() {
+		t.Error("parser with feed.body must fetch articles")
+	}
+}
+
+func assertDigest(t *testing.T, got, want entity.NewsDigest) {
+	t.Helper()
+
+	if got.Title != want.Title || got.Link != want.Link || got.Source != want.Source ||
+		got.Category != want.Category || got.Summary != want.Summary {
+		t.Errorf("digest = %+v, want %+v", got, want)
+	}
+	if !got.PublishedAt.Equal(want.PublishedAt) {
+		t.Errorf("%s: PublishedAt = %v, want %v", want.Link, got.PublishedAt, want.PublishedAt)
+	}
+}
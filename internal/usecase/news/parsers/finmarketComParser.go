@@ -2,28 +2,32 @@ package parsers
 
 import (
 	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/lib/fetcher"
+	"context"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
-	"io/ioutil"
 	"log"
 	"log/slog"
-	"net/http"
 	"strings"
 	"time"
-
-	"golang.org/x/text/encoding/charmap"
-	"golang.org/x/text/transform"
 )
 
+const finmarketDefaultEncoding = "windows-1251"
+
 type FinmarketComParser struct {
-	log *slog.Logger
+	log      *slog.Logger
+	fetcher  *fetcher.Fetcher
+	encoding string
 }
 
-func NewFinmarketComParser(log *slog.Logger) *FinmarketComParser {
-	return &FinmarketComParser{log: log}
+func NewFinmarketComParser(log *slog.Logger, fetcher *fetcher.Fetcher, encoding string) *FinmarketComParser {
+	if encoding == "" {
+		encoding = finmarketDefaultEncoding
+	}
+	return &FinmarketComParser{log: log, fetcher: fetcher, encoding: encoding}
 }
 
-func (p *FinmarketComParser) ParseNewsDigest(body string) ([]entity.NewsDigest, error) {
+func (p *FinmarketComParser) ParseNewsDigest(ctx context.Context, pageURL, body string) ([]entity.NewsDigest, error) {
 	p.log.Info("finmarket.com news parsing")
 
 	// Парсим документ с учетом кодировки
@@ -66,6 +70,7 @@ func (p *FinmarketComParser) ParseNewsDigest(body string) ([]entity.NewsDigest,
 						Link:        fullLink,
 						PublishedAt: currentDate,
 						Source:      "finmarket.ru",
+						Category:    categoryFromLink(fullLink),
 					}
 					newsList = append(newsList, news)
 				})
@@ -77,22 +82,7 @@ func (p *FinmarketComParser) ParseNewsDigest(body string) ([]entity.NewsDigest,
 }
 
 func (p *FinmarketComParser) parseDate(dateStr string) (time.Time, error) {
-	replacer := strings.NewReplacer(
-		"января", "January",
-		"февраля", "February",
-		"марта", "March",
-		"апреля", "April",
-		"мая", "May",
-		"июня", "June",
-		"июля", "July",
-		"августа", "August",
-		"сентября", "September",
-		"октября", "October",
-		"ноября", "November",
-		"декабря", "December",
-	)
-
-	dateStr = replacer.Replace(dateStr)
+	dateStr = replaceRussianMonths(dateStr)
 
 	dateStr = strings.TrimSpace(dateStr)
 
@@ -104,7 +94,7 @@ func (p *FinmarketComParser) parseDate(dateStr string) (time.Time, error) {
 	return parsedDate, nil
 }
 
-func (p *FinmarketComParser) ParseNews(body string, newsDigest entity.NewsDigest) (*entity.News, error) {
+func (p *FinmarketComParser) ParseNews(ctx context.Context, body string, newsDigest entity.NewsDigest) (*entity.News, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
 	if err != nil {
 		p.log.Warn("Ошибка при парсинге HTML", slog.String("error", err.Error()))
@@ -123,32 +113,12 @@ func (p *FinmarketComParser) ParseNews(body string, newsDigest entity.NewsDigest
 		Title:       newsDigest.Title,
 		Link:        newsDigest.Link,
 		Source:      newsDigest.Source,
+		Category:    newsDigest.Category,
 		Text:        contentBuilder.String(),
 		PublishedAt: newsDigest.PublishedAt,
 	}, nil
 }
 
-func (p *FinmarketComParser) FetchHTML(url string) (string, error) {
-
-	res, err := http.Get(url)
-	if err != nil {
-		p.log.Warn("Error fetching HTML:", slog.String("error", err.Error()))
-		return "", fmt.Errorf("error fetching HTML: %v", err)
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != http.StatusOK {
-		p.log.Warn("invalid status code: %d", res.StatusCode)
-		return "", fmt.Errorf("invalid status code: %d", res.StatusCode)
-	}
-
-	utf8Reader := transform.NewReader(res.Body, charmap.Windows1251.NewDecoder()) // Читаем декодированное тело
-
-	body, err := ioutil.ReadAll(utf8Reader)
-	if err != nil {
-		p.log.Warn("Error read body", slog.String("error", err.Error()))
-		return "", fmt.Errorf("error read body: %v", err)
-	}
-
-	return string(body), nil
+func (p *FinmarketComParser) FetchHTML(ctx context.Context, url string) (string, error) {
+	return fetchHTML(ctx, p.fetcher, p.log, url, p.encoding)
 }
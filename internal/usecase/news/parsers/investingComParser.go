@@ -1,24 +1,26 @@
 package parsers
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
 	"regexp"
 	"strings"
 	"time"
 
 	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/lib/fetcher"
 	"github.com/PuerkitoBio/goquery"
 )
 
 type InvestingComParser struct {
-	log *slog.Logger
+	log      *slog.Logger
+	fetcher  *fetcher.Fetcher
+	encoding string
 }
 
-func NewInvestingComParser(log *slog.Logger) *InvestingComParser {
-	return &InvestingComParser{log: log}
+func NewInvestingComParser(log *slog.Logger, fetcher *fetcher.Fetcher, encoding string) *InvestingComParser {
+	return &InvestingComParser{log: log, fetcher: fetcher, encoding: encoding}
 }
 
 //
@@ -55,7 +57,7 @@ func NewInvestingComParser(log *slog.Logger) *InvestingComParser {
 //
 //}
 
-func (p *InvestingComParser) ParseNewsDigest(body string) ([]entity.NewsDigest, error) {
+func (p *InvestingComParser) ParseNewsDigest(ctx context.Context, pageURL, body string) ([]entity.NewsDigest, error) {
 	p.log.Info("investing.com news parsing")
 
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
@@ -79,6 +81,7 @@ func (p *InvestingComParser) ParseNewsDigest(body string) ([]entity.NewsDigest,
 				Title:       title,
 				Link:        link,
 				Source:      source,
+				Category:    categoryFromLink(link),
 				PublishedAt: parsedTime,
 			})
 		} else {
@@ -89,7 +92,7 @@ func (p *InvestingComParser) ParseNewsDigest(body string) ([]entity.NewsDigest,
 
 }
 
-func (p *InvestingComParser) ParseNews(body string, newsDigest entity.NewsDigest) (*entity.News, error) {
+func (p *InvestingComParser) ParseNews(ctx context.Context, body string, newsDigest entity.NewsDigest) (*entity.News, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
 	if err != nil {
 		p.log.Warn("Ошибка при парсинге HTML", slog.String("error", err.Error()))
@@ -108,33 +111,15 @@ func (p *InvestingComParser) ParseNews(body string, newsDigest entity.NewsDigest
 		Title:       newsDigest.Title,
 		Link:        newsDigest.Link,
 		Source:      newsDigest.Source,
+		Category:    newsDigest.Category,
 		Text:        newsBuilder.String(),
 		PublishedAt: newsDigest.PublishedAt,
 	}, nil
 
 }
 
-func (p *InvestingComParser) FetchHTML(url string) (string, error) {
-
-	res, err := http.Get(url)
-	if err != nil {
-		p.log.Warn("Error fetching HTML:", slog.String("error", err.Error()))
-		return "", fmt.Errorf("error fetching HTML: %v", err)
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != http.StatusOK {
-		p.log.Warn("invalid status code: %d", res.StatusCode)
-		return "", fmt.Errorf("invalid status code: %d", res.StatusCode)
-	}
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		p.log.Warn("Error read body", slog.String("error", err.Error()))
-		return "", fmt.Errorf("error read body: %v", err)
-	}
-
-	return string(body), nil
+func (p *InvestingComParser) FetchHTML(ctx context.Context, url string) (string, error) {
+	return fetchHTML(ctx, p.fetcher, p.log, url, p.encoding)
 }
 
 func (p *InvestingComParser) parseTime(timeStr string) time.Time {
@@ -0,0 +1,72 @@
+package parsers
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"AIChallengeNewsAPI/internal/interfaces"
+	"AIChallengeNewsAPI/internal/lib/fetcher"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// NewParser создаёт парсер по полю parser из описания источника.
+func NewParser(source config.SourceConfig, scraper config.ScraperConfig, log *slog.Logger) (interfaces.Parser, error) {
+	log = log.With(slog.String("source", source.Name))
+	f := newFetcher(source, scraper, log)
+
+	switch source.Parser {
+	case "investing":
+		return NewInvestingComParser(log, f, source.Encoding), nil
+	case "finmarket":
+		return NewFinmarketComParser(log, f, source.Encoding), nil
+	case "selector":
+		return NewSelectorParser(log, f, source)
+	case "feed":
+		return NewFeedParser(log, f, source)
+	default:
+		return nil, fmt.Errorf("unknown parser type %q for source %q", source.Parser, source.Name)
+	}
+}
+
+func newFetcher(source config.SourceConfig, scraper config.ScraperConfig, log *slog.Logger) *fetcher.Fetcher {
+	options := fetcher.Options{
+		Source:      source.Name,
+		Timeout:     scraper.HTTPTimeout,
+		Retries:     scraper.HTTPRetries,
+		UserAgent:   scraper.UserAgent,
+		Headers:     source.HTTP.Headers,
+		MaxBodySize: scraper.MaxBodySize,
+	}
+	if source.HTTP.Timeout > 0 {
+		options.Timeout = source.HTTP.Timeout
+	}
+	if source.HTTP.Retries != nil {
+		options.Retries = *source.HTTP.Retries
+	}
+	if source.HTTP.UserAgent != "" {
+		options.UserAgent = source.HTTP.UserAgent
+	}
+	if source.HTTP.MaxBodySize > 0 {
+		options.MaxBodySize = source.HTTP.MaxBodySize
+	}
+	return fetcher.New(log, options)
+}
+
+// fetchHTML — общая реализация Parser.FetchHTML: загрузка через fetcher
+// и перекод тела в UTF-8 по обнаруженной кодировке; encoding источника
+// используется, только если страница сама её не указывает.
+func fetchHTML(ctx context.Context, f *fetcher.Fetcher, log *slog.Logger, url string, encoding string) (string, error) {
+	res, err := f.Fetch(ctx, url)
+	if err != nil {
+		log.Warn("Error fetching HTML", slog.String("url", url), slog.String("error", err.Error()))
+		return "", fmt.Errorf("error fetching HTML: %w", err)
+	}
+
+	body, err := res.Text(encoding)
+	if err != nil {
+		log.Warn("Error read body", slog.String("url", url), slog.String("error", err.Error()))
+		return "", fmt.Errorf("error read body: %w", err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,178 @@
+package parsers
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/lib/fetcher"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// SelectorParser — универсальный парсер, вся разметка сайта для которого
+// задаётся в конфигурации источника (config.SelectorConfig).
+type SelectorParser struct {
+	log      *slog.Logger
+	fetcher  *fetcher.Fetcher
+	source   string
+	encoding string
+	spec     config.SelectorConfig
+	location *time.Location
+}
+
+func NewSelectorParser(log *slog.Logger, fetcher *fetcher.Fetcher, source config.SourceConfig) (*SelectorParser, error) {
+	spec := source.Selector
+
+	for _, selector := range []string{spec.Item, spec.Title, spec.Link, spec.Date, spec.Provider, spec.Body} {
+		if selector == "" {
+			continue
+		}
+		if _, err := cascadia.ParseGroup(selector); err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %v", selector, err)
+		}
+	}
+
+	location := time.UTC
+	if spec.Timezone != "" {
+		loc, err := time.LoadLocation(spec.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		location = loc
+	}
+	if spec.LinkAttr == "" {
+		spec.LinkAttr = "href"
+	}
+
+	return &SelectorParser{
+		log:      log,
+		fetcher:  fetcher,
+		source:   source.Name,
+		encoding: source.Encoding,
+		spec:     spec,
+		location: location,
+	}, nil
+}
+
+func (p *SelectorParser) ParseNewsDigest(ctx context.Context, pageURL, body string) ([]entity.NewsDigest, error) {
+	p.log.Info("selector news parsing")
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page url %q: %v", pageURL, err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		p.log.Warn("Ошибка при парсинге HTML", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var newsArray []entity.NewsDigest
+	doc.Find(p.spec.Item).Each(func(i int, s *goquery.Selection) {
+		linkSelection := p.find(s, p.spec.Link)
+		href, exists := linkSelection.Attr(p.spec.LinkAttr)
+		if !exists || strings.TrimSpace(href) == "" {
+			p.log.Warn("news link not found", slog.Int("item", i))
+			return
+		}
+		// Ссылки бывают абсолютными, от корня ("/news/1") и относительными
+		// ("1.html"), поэтому разрешаются от адреса страницы списка.
+		ref, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			p.log.Warn("invalid news link", slog.String("href", href), slog.String("error", err.Error()))
+			return
+		}
+		link := base.ResolveReference(ref).String()
+
+		title := cleanText(p.find(s, p.spec.Title).Text())
+
+		source := p.source
+		if p.spec.Provider != "" {
+			if provider := cleanText(s.Find(p.spec.Provider).Text()); provider != "" {
+				source = provider
+			}
+		}
+
+		publishedAt := time.Now()
+		if p.spec.Date != "" {
+			parsed, err := p.parseDate(p.value(s.Find(p.spec.Date), p.spec.DateAttr))
+			if err != nil {
+				p.log.Warn("failed to parse date", slog.String("link", link), slog.String("error", err.Error()))
+			} else {
+				publishedAt = parsed
+			}
+		}
+
+		newsArray = append(newsArray, entity.NewsDigest{
+			Title:       title,
+			Link:        link,
+			Source:      source,
+			Category:    categoryFromLink(link),
+			PublishedAt: publishedAt,
+		})
+	})
+
+	return newsArray, nil
+}
+
+func (p *SelectorParser) ParseNews(ctx context.Context, body string, newsDigest entity.NewsDigest) (*entity.News, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		p.log.Warn("Ошибка при парсинге HTML", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var newsBuilder strings.Builder
+	doc.Find(p.spec.Body).Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if text == "" {
+			return
+		}
+		newsBuilder.WriteString(text)
+		newsBuilder.WriteString("\n")
+	})
+
+	return &entity.News{
+		Title:       newsDigest.Title,
+		Link:        newsDigest.Link,
+		Source:      newsDigest.Source,
+		Category:    newsDigest.Category,
+		Text:        newsBuilder.String(),
+		PublishedAt: newsDigest.PublishedAt,
+	}, nil
+}
+
+func (p *SelectorParser) FetchHTML(ctx context.Context, url string) (string, error) {
+	return fetchHTML(ctx, p.fetcher, p.log, url, p.encoding)
+}
+
+func (p *SelectorParser) parseDate(dateStr string) (time.Time, error) {
+	dateStr = strings.TrimSpace(replaceRussianMonths(dateStr))
+	return time.ParseInLocation(p.spec.DateLayout, dateStr, p.location)
+}
+
+// find ищет selector внутри элемента списка; пустой selector означает сам элемент.
+func (p *SelectorParser) find(s *goquery.Selection, selector string) *goquery.Selection {
+	if selector == "" {
+		return s
+	}
+	return s.Find(selector).First()
+}
+
+func (p *SelectorParser) value(s *goquery.Selection, attr string) string {
+	if attr == "" {
+		return s.First().Text()
+	}
+	return s.First().AttrOr(attr, "")
+}
+
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,187 @@
+package parsers
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"AIChallengeNewsAPI/internal/entity"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testListingURL = "https://example.ru/news/markets/index.html"
+
+func testSelectorConfig() config.SelectorConfig {
+	return config.SelectorConfig{
+		Item:       "div.news-list > div.item",
+		Title:      ".title",
+		Link:       "a.title",
+		Date:       "span.date",
+		DateLayout: "2 January 2006, 15:04",
+		Timezone:   "Europe/Moscow",
+		Provider:   "span.provider",
+		Body:       "div.article-text p",
+	}
+}
+
+func newTestSelectorParser(t *testing.T, spec config.SelectorConfig) *SelectorParser {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	parser, err := NewSelectorParser(log, nil, config.SourceConfig{Name: "example", Selector: spec})
+	if err != nil {
+		t.Fatalf("NewSelectorParser: %v", err)
+	}
+	return parser
+}
+
+func readTestdata(t *testing.T, name string) string {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestSelectorParserParseNewsDigest(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	parser := newTestSelectorParser(t, testSelectorConfig())
+
+	before := time.Now()
+	got, err := parser.ParseNewsDigest(context.Background(), testListingURL, readTestdata(t, "listing.html"))
+	if err != nil {
+		t.Fatalf("ParseNewsDigest: %v", err)
+	}
+
+	// Элемент без ссылки пропускается; у неразобранной даты время загрузки.
+	want := []entity.NewsDigest{
+		{
+			Title:       "Банк России сохранил ключевую ставку",
+			Link:        "https://example.ru/news/economy/1",
+			Source:      "Интерфакс",
+			Category:    "economy",
+			PublishedAt: time.Date(2024, 10, 25, 13, 30, 0, 0, msk),
+		},
+		{
+			Title:       "Нефть подорожала",
+			Link:        "https://example.ru/news/markets/2",
+			Source:      "example",
+			Category:    "markets",
+			PublishedAt: time.Date(2024, 10, 24, 9, 15, 0, 0, msk),
+		},
+		{
+			Title:       "Индекс Мосбиржи вырос",
+			Link:        "https://example.ru/news/markets/3.html",
+			Source:      "example",
+			Category:    "markets",
+			PublishedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, msk),
+		},
+		{
+			Title:       "Рубль укрепился",
+			Link:        "https://cdn.example.ru/news/world/4",
+			Source:      "example",
+			Category:    "world",
+			PublishedAt: time.Date(2024, 5, 7, 18, 5, 0, 0, msk),
+		},
+		{
+			Title:    "Минфин разместил ОФЗ",
+			Link:     "https://example.ru/news/finance/5",
+			Source:   "example",
+			Category: "finance",
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d digests, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want[:len(want)-1] {
+		assertDigest(t, got[i], want[i])
+	}
+
+	last := got[len(got)-1]
+	want[len(want)-1].PublishedAt = last.PublishedAt
+	assertDigest(t, last, want[len(want)-1])
+	if last.PublishedAt.Before(before) || last.PublishedAt.After(time.Now()) {
+		t.Errorf("PublishedAt for an unparsable date = %v, want the time of parsing", last.PublishedAt)
+	}
+}
+
+func TestSelectorParserParseNewsDigestItemAsLink(t *testing.T) {
+	spec := config.SelectorConfig{Item: "a.headline"}
+	parser := newTestSelectorParser(t, spec)
+
+	body := `<html><body>
+		<a class="headline" href="../economy/7">Ставка ЦБ</a>
+		<a class="headline" data-url="/ignored">Без href</a>
+	</body></html>`
+	got, err := parser.ParseNewsDigest(context.Background(), testListingURL, body)
+	if err != nil {
+		t.Fatalf("ParseNewsDigest: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d digests, want 1: %+v", len(got), got)
+	}
+	if got[0].Title != "Ставка ЦБ" || got[0].Link != "https://example.ru/news/economy/7" || got[0].Source != "example" {
+		t.Errorf("digest = %+v", got[0])
+	}
+}
+
+func TestSelectorParserParseNewsDigestInvalidPageURL(t *testing.T) {
+	parser := newTestSelectorParser(t, testSelectorConfig())
+
+	if _, err := parser.ParseNewsDigest(context.Background(), "://example.ru", readTestdata(t, "listing.html")); err == nil {
+		t.Error("expected an error for an invalid page url")
+	}
+}
+
+func TestSelectorParserParseNews(t *testing.T) {
+	parser := newTestSelectorParser(t, testSelectorConfig())
+	digest := entity.NewsDigest{
+		Title:       "Банк России сохранил ключевую ставку",
+		Link:        "https://example.ru/news/economy/1",
+		Source:      "Интерфакс",
+		Category:    "economy",
+		PublishedAt: time.Date(2024, 10, 25, 10, 30, 0, 0, time.UTC),
+	}
+
+	got, err := parser.ParseNews(context.Background(), readTestdata(t, "article.html"), digest)
+	if err != nil {
+		t.Fatalf("ParseNews: %v", err)
+	}
+
+	want := entity.News{
+		Title:       digest.Title,
+		Link:        digest.Link,
+		Source:      digest.Source,
+		Category:    digest.Category,
+		Text:        "Совет директоров сохранил ставку на уровне 21%.\nСледующее заседание — в декабре.\n",
+		PublishedAt: digest.PublishedAt,
+	}
+	if *got != want {
+		t.Errorf("ParseNews() = %+v, want %+v", *got, want)
+	}
+}
+
+func TestNewSelectorParserRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		spec config.SelectorConfig
+	}{
+		{name: "invalid selector", spec: config.SelectorConfig{Item: "div[", Link: "a"}},
+		{name: "invalid body selector", spec: config.SelectorConfig{Item: "div.item", Body: "p:unknown"}},
+		{name: "unknown timezone", spec: config.SelectorConfig{Item: "div.item", Timezone: "Mars/Olympus"}},
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSelectorParser(log, nil, config.SourceConfig{Name: "example", Selector: tt.spec}); err == nil {
+				t.Error("NewSelectorParser succeeded, want an error")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package parsers
 
 import (
+	"context"
 	"log"
 	"log/slog"
 	"strings"
@@ -18,7 +19,7 @@ func NewTradingviewComParser(log *slog.Logger) *TradingviewComParser {
 	return &TradingviewComParser{log: log}
 }
 
-func (p *TradingviewComParser) ParseNewsDigest(body string) ([]entity.NewsDigest, error) {
+func (p *TradingviewComParser) ParseNewsDigest(ctx context.Context, pageURL, body string) ([]entity.NewsDigest, error) {
 	p.log.Info("tradingview.com news parsing")
 
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
@@ -51,6 +52,7 @@ func (p *TradingviewComParser) ParseNewsDigest(body string) ([]entity.NewsDigest
 				Title:       title,
 				Link:        link,
 				Source:      source,
+				Category:    categoryFromLink(link),
 				PublishedAt: parsedTime,
 			})
 		} else {
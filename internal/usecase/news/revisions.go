@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"AIChallengeNewsAPI/internal/interfaces"
+	"AIChallengeNewsAPI/internal/lib/metrics"
+	"AIChallengeNewsAPI/internal/lib/textdiff"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var errEmptyArticle = errors.New("article text is empty")
+
+// revisionSettings — параметры перепроверки опубликованных статей.
+type revisionSettings struct {
+	window   time.Duration
+	interval time.Duration
+	batch    int
+}
+
+// GetNewsRevisions возвращает найденные изменения статьи, новые первыми.
+func (ucNews *NewsUseCase) GetNewsRevisions(ctx context.Context, id int) ([]entity.NewsRevision, error) {
+	if _, err := ucNews.GetNewsById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	revisions, err := ucNews.repo.GetNewsRevisions(ctx, id)
+	if err != nil {
+		ucNews.log.Warn("failed to get news revisions", slog.Int("id", id), slog.String("error", err.Error()))
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// checkRevisions заново загружает недавние статьи источника и сравнивает
+// хеш содержимого с сохранённым. Изменённая статья обновляется, а diff
+// сохраняется в news_revisions. Выполняется внутри запуска источника,
+// поэтому реплики не перепроверяют один источник одновременно.
+//
+// Источники, новости которых целиком собираются из дайджеста, не
+// перепроверяются: страницы статьи у них не загружаются.
+func (ucNews *NewsUseCase) checkRevisions(ctx context.Context, source *newsSource) {
+	if ucNews.revisions.window <= 0 {
+		return
+	}
+	if digestParser, ok := source.parser.(interfaces.DigestOnlyParser); ok && !digestParser.FetchesArticles() {
+		return
+	}
+
+	now := time.Now()
+	storeCtx, cancel := context.WithTimeout(ctx, ucNews.timeouts.store)
+	newsList, err := ucNews.repo.GetNewsForRecheck(storeCtx, entity.NewsRecheckFilter{
+		SourceKey:      source.name,
+		PublishedAfter: now.Add(-ucNews.revisions.window),
+		CheckedBefore:  now.Add(-ucNews.revisions.interval),
+		Limit:          ucNews.revisions.batch,
+	})
+	cancel()
+	if err != nil {
+		ucNews.log.Warn("failed to select news for recheck", slog.String("source", source.name),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	revised := 0
+	for _, stored := range newsList {
+		if ctx.Err() != nil {
+			return
+		}
+
+		changed, err := ucNews.recheckNews(ctx, stored, source)
+		if err != nil {
+			if ctx.Err() == nil {
+				ucNews.log.Warn("failed to recheck news", slog.String("source", source.name),
+					slog.String("url", stored.Link), slog.String("error", err.Error()))
+			}
+			continue
+		}
+		if changed {
+			revised++
+		}
+	}
+
+	if len(newsList) > 0 {
+		ucNews.log.Info("news rechecked", slog.String("source", source.name),
+			slog.Int("checked", len(newsList)), slog.Int("revised", revised))
+	}
+}
+
+// recheckNews загружает статью заново и сохраняет её новую версию, если
+// содержимое изменилось. Неудачная загрузка тоже отмечается как проверка,
+// иначе недоступная статья занимала бы место в каждой следующей выборке.
+func (ucNews *NewsUseCase) recheckNews(ctx context.Context, stored entity.News, source *newsSource) (bool, error) {
+	previous := stored.ContentHash
+	if previous == "" {
+		previous = entity.ContentHash(stored.Title, stored.Text)
+	}
+
+	fresh, err := ucNews.getNews(ctx, *stored.ConvertToNewsDigest(), source)
+	if err == nil && strings.TrimSpace(fresh.Text) == "" {
+		// Пустой текст — скорее сбой разбора, чем правка статьи.
+		err = errEmptyArticle
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			ucNews.markChecked(ctx, stored.ID, stored.ContentHash)
+		}
+		return false, err
+	}
+	metrics.ScrapeRechecked.WithLabelValues(source.name).Inc()
+
+	current := entity.ContentHash(fresh.Title, fresh.Text)
+	if current == previous {
+		return false, ucNews.markChecked(ctx, stored.ID, current)
+	}
+
+	revision := entity.NewsRevision{
+		NewsID:       stored.ID,
+		PreviousHash: previous,
+		ContentHash:  current,
+		Diff:         textdiff.Unified(stored.Title+"\n"+stored.Text, fresh.Title+"\n"+fresh.Text),
+		DetectedAt:   time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ucNews.timeouts.store)
+	defer cancel()
+	if _, err := ucNews.repo.AddNewsRevision(ctx, *fresh, revision); err != nil {
+		return false, err
+	}
+	metrics.ScrapeRevisions.WithLabelValues(source.name).Inc()
+	ucNews.log.Info("news revised", slog.String("source", source.name), slog.Int("id", stored.ID),
+		slog.String("url", stored.Link))
+	return true, nil
+}
+
+func (ucNews *NewsUseCase) markChecked(ctx context.Context, id int, contentHash string) error {
+	ctx, cancel := context.WithTimeout(ctx, ucNews.timeouts.store)
+	defer cancel()
+	return ucNews.repo.MarkNewsChecked(ctx, id, contentHash, time.Now())
+}
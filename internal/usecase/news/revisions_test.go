@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"AIChallengeNewsAPI/internal/repository/memory"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckRevisionsDetectsChangedArticle(t *testing.T) {
+	site := &testSite{text: "Банк России сохранил ставку."}
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	repo := memory.NewRepository()
+	ucNews := newTestUseCase(t, repo, selectorSource("testsite", server.URL))
+	source := ucNews.sources[0]
+	ctx := context.Background()
+
+	ucNews.scrapeAndStoreNews(ctx, source)
+
+	stored, ok := findNews(t, repo, server.URL+"/news/1")
+	if !ok {
+		t.Fatal("article not stored")
+	}
+	if stored.Source == source.name {
+		t.Fatalf("stored source %q equals the source name, the test would not catch a recheck by source", stored.Source)
+	}
+
+	site.setText("Банк России повысил ставку.")
+	ucNews.scrapeAndStoreNews(ctx, source)
+
+	revisions, err := ucNews.GetNewsRevisions(ctx, stored.ID)
+	if err != nil {
+		t.Fatalf("GetNewsRevisions: %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("got %d revisions, want 1", len(revisions))
+	}
+	if !strings.Contains(revisions[0].Diff, "+Банк России повысил ставку.") {
+		t.Errorf("diff does not contain the new text:\n%s", revisions[0].Diff)
+	}
+
+	updated, err := repo.GetNewsById(ctx, stored.ID)
+	if err != nil {
+		t.Fatalf("GetNewsById: %v", err)
+	}
+	if !strings.Contains(updated.Text, "повысил") {
+		t.Errorf("article text was not updated: %q", updated.Text)
+	}
+	if updated.ContentHash != revisions[0].ContentHash {
+		t.Errorf("content hash %q, want %q", updated.ContentHash, revisions[0].ContentHash)
+	}
+
+	ucNews.scrapeAndStoreNews(ctx, source)
+	if revisions, _ := ucNews.GetNewsRevisions(ctx, stored.ID); len(revisions) != 1 {
+		t.Errorf("unchanged article produced a revision: got %d revisions, want 1", len(revisions))
+	}
+}
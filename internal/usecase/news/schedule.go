@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"AIChallengeNewsAPI/internal/config"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	defaultTradingInterval  = 2 * time.Minute
+	defaultOffHoursInterval = time.Hour
+)
+
+// Москва не переходит на летнее время, поэтому фиксированная зона надёжнее
+// time.LoadLocation в образах без tzdata.
+var moscow = time.FixedZone("MSK", 3*60*60)
+
+// schedule возвращает время следующего запуска парсинга источника.
+type schedule interface {
+	Next(after time.Time) time.Time
+}
+
+func newSchedule(cfg config.ScheduleConfig, interval time.Duration) (schedule, error) {
+	switch {
+	case cfg.Cron != "":
+		return cron.ParseStandard(cfg.Cron)
+	case cfg.Profile == "moex":
+		return newMarketHoursSchedule(cfg), nil
+	default:
+		return intervalSchedule(interval), nil
+	}
+}
+
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(s))
+}
+
+// marketHoursSchedule часто опрашивает источник в основную сессию Московской
+// биржи (10:00–18:50 МСК по рабочим дням) и редко — ночью и в выходные.
+type marketHoursSchedule struct {
+	tradingInterval  time.Duration
+	offHoursInterval time.Duration
+	holidays         map[string]struct{}
+}
+
+func newMarketHoursSchedule(cfg config.ScheduleConfig) *marketHoursSchedule {
+	s := &marketHoursSchedule{
+		tradingInterval:  cfg.TradingInterval,
+		offHoursInterval: cfg.OffHoursInterval,
+		holidays:         make(map[string]struct{}, len(cfg.Holidays)),
+	}
+	if s.tradingInterval == 0 {
+		s.tradingInterval = defaultTradingInterval
+	}
+	if s.offHoursInterval == 0 {
+		s.offHoursInterval = defaultOffHoursInterval
+	}
+	for _, holiday := range cfg.Holidays {
+		s.holidays[holiday] = struct{}{}
+	}
+	return s
+}
+
+func (s *marketHoursSchedule) Next(after time.Time) time.Time {
+	local := after.In(moscow)
+
+	if s.isTradingDay(local) {
+		open, closeAt := sessionBounds(local)
+		if !local.Before(open) && local.Before(closeAt) {
+			next := local.Add(s.tradingInterval)
+			if next.After(closeAt) {
+				// Последний опрос — в момент закрытия сессии.
+				next = closeAt
+			}
+			return next
+		}
+	}
+
+	next := local.Add(s.offHoursInterval)
+	if open := s.nextOpen(local); open.Before(next) {
+		next = open
+	}
+	return next
+}
+
+func (s *marketHoursSchedule) isTradingDay(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := s.holidays[t.Format(time.DateOnly)]
+	return !holiday
+}
+
+func (s *marketHoursSchedule) nextOpen(after time.Time) time.Time {
+	day := after
+	for i := 0; i < 31; i++ {
+		open, _ := sessionBounds(day)
+		if open.After(after) && s.isTradingDay(day) {
+			return open
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return after.Add(s.offHoursInterval)
+}
+
+func sessionBounds(day time.Time) (time.Time, time.Time) {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, 10, 0, 0, 0, moscow), time.Date(y, m, d, 18, 50, 0, 0, moscow)
+}
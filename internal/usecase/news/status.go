@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"AIChallengeNewsAPI/internal/entity"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var errSchemaNotReady = errors.New("database migrations are not applied")
+
+// sourceState запоминает итоги последних запусков источника для /status.
+type sourceState struct {
+	mu            sync.Mutex
+	lastRunAt     time.Time
+	lastStatus    string
+	lastSuccessAt time.Time
+	lastError     string
+	lastErrorAt   time.Time
+}
+
+func (s *sourceState) update(run entity.ScrapeRun) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastRunAt = run.StartedAt
+	s.lastStatus = run.Status
+	if run.Status == entity.RunStatusSucceeded || run.Status == entity.RunStatusPartial {
+		s.lastSuccessAt = run.StartedAt
+	}
+	if run.Error != "" {
+		s.lastError = run.Error
+		s.lastErrorAt = run.StartedAt
+	}
+}
+
+// CheckStorage проверяет, что база доступна и все миграции применены.
+func (ucNews *NewsUseCase) CheckStorage(ctx context.Context) error {
+	if err := ucNews.repo.Ping(ctx); err != nil {
+		return err
+	}
+	ready, err := ucNews.repo.SchemaReady(ctx)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		return errSchemaNotReady
+	}
+	return nil
+}
+
+// SourceStatuses возвращает состояние каждого включённого источника.
+// Источник, ещё ни разу не отработавший успешно, считается устаревшим,
+// только когда с момента старта прошло больше staleThreshold.
+func (ucNews *NewsUseCase) SourceStatuses() []entity.SourceStatus {
+	now := time.Now()
+	statuses := make([]entity.SourceStatus, 0, len(ucNews.sources))
+	for _, source := range ucNews.sources {
+		source.state.mu.Lock()
+		status := entity.SourceStatus{
+			Source:       source.name,
+			LastStatus:   source.state.lastStatus,
+			LastError:    source.state.lastError,
+			PendingRetry: source.failures.size(),
+			QueueDepth:   source.queueDepth.Load(),
+		}
+		if !source.state.lastRunAt.IsZero() {
+			lastRunAt := source.state.lastRunAt
+			status.LastRunAt = &lastRunAt
+		}
+		if !source.state.lastErrorAt.IsZero() {
+			lastErrorAt := source.state.lastErrorAt
+			status.LastErrorAt = &lastErrorAt
+		}
+		lastSuccess := ucNews.startedAt
+		if !source.state.lastSuccessAt.IsZero() {
+			lastSuccessAt := source.state.lastSuccessAt
+			status.LastSuccessAt = &lastSuccessAt
+			lastSuccess = lastSuccessAt
+		}
+		source.state.mu.Unlock()
+
+		status.Stale = now.Sub(lastSuccess) > ucNews.staleThreshold
+		statuses = append(statuses, status)
+	}
+	return statuses
+}